@@ -0,0 +1,57 @@
+// Package adapters renders common third-party types by stable names
+// instead of their internal layout, so that models using them are readable
+// and do not change when the libraries do. Types are matched by package
+// path and name, so this package does not depend on the libraries.
+//
+//	model, err := model_reflect.New(v, adapters.Option())
+package adapters
+
+import (
+	"reflect"
+
+	"github.com/go-modern/model_reflect"
+)
+
+var (
+	// UUID renders github.com/google/uuid.UUID as uuid and NullUUID as
+	// ?uuid.
+	UUID model_reflect.Extension = named{"uuid/v1", map[string]string{
+		"github.com/google/uuid.UUID":     "uuid",
+		"github.com/google/uuid.NullUUID": "?uuid",
+	}}
+	// Decimal renders github.com/shopspring/decimal.Decimal as decimal and
+	// NullDecimal as ?decimal.
+	Decimal model_reflect.Extension = named{"decimal/v1", map[string]string{
+		"github.com/shopspring/decimal.Decimal":     "decimal",
+		"github.com/shopspring/decimal.NullDecimal": "?decimal",
+	}}
+	// ObjectID renders the MongoDB ObjectID of the v1 and v2 drivers as
+	// objectid.
+	ObjectID model_reflect.Extension = named{"objectid/v1", map[string]string{
+		"go.mongodb.org/mongo-driver/bson/primitive.ObjectID": "objectid",
+		"go.mongodb.org/mongo-driver/v2/bson.ObjectID":        "objectid",
+	}}
+)
+
+// Option registers every adapter of this package.
+func Option() model_reflect.Option {
+	return model_reflect.WithExtensions(UUID, Decimal, ObjectID)
+}
+
+// named renders the types in types, keyed by package path and name.
+type named struct {
+	name  string
+	types map[string]string
+}
+
+func (n named) Name() string {
+	return "adapters/" + n.name
+}
+
+func (n named) Describe(t reflect.Type) (model_reflect.Fragment, bool) {
+	if t.PkgPath() == "" {
+		return model_reflect.Fragment{}, false
+	}
+	repr, ok := n.types[t.PkgPath()+"."+t.Name()]
+	return model_reflect.Fragment{Repr: repr}, ok
+}
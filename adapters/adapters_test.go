@@ -0,0 +1,29 @@
+package adapters_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+	"github.com/go-modern/model_reflect/adapters"
+)
+
+// UUID has the name but not the package of the google type.
+type UUID [16]byte
+
+func TestAdapters(t *testing.T) {
+	names := map[string]bool{}
+	for _, ext := range []model_reflect.Extension{adapters.UUID, adapters.Decimal, adapters.ObjectID} {
+		if names[ext.Name()] {
+			t.Errorf("duplicate name %s", ext.Name())
+		}
+		names[ext.Name()] = true
+		if _, ok := ext.Describe(reflect.TypeOf(UUID{})); ok {
+			t.Errorf("%s claims a local type", ext.Name())
+		}
+	}
+	model, err := model_reflect.New(struct{ ID UUID }{}, adapters.Option())
+	if err != nil || model.String() != "{ ID:[16]uint8 }" {
+		t.Errorf("%s [%v]", model, err)
+	}
+}
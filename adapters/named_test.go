@@ -0,0 +1,65 @@
+package adapters
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+// Stand-ins shaped like the library types, which this module does not
+// import.
+type (
+	uuidShape     [16]byte
+	nullUUIDShape struct {
+		UUID  uuidShape
+		Valid bool
+	}
+	decimalShape struct {
+		value *big.Int
+		exp   int32
+	}
+	nullDecimalShape struct {
+		Decimal decimalShape
+		Valid   bool
+	}
+	objectIDShape [12]byte
+)
+
+// as returns n matching local in place of the library type named key.
+func (n named) as(t *testing.T, key string, local reflect.Type) named {
+	t.Helper()
+	repr, ok := n.types[key]
+	if !ok {
+		t.Fatalf("%s: no %s", n.Name(), key)
+	}
+	return named{n.name, map[string]string{local.PkgPath() + "." + local.Name(): repr}}
+}
+
+func TestNamedShapes(t *testing.T) {
+	for _, c := range []struct {
+		ext  model_reflect.Extension
+		key  string
+		v    any
+		want string
+	}{
+		{UUID, "github.com/google/uuid.UUID", uuidShape{}, "uuid"},
+		{UUID, "github.com/google/uuid.NullUUID", nullUUIDShape{}, "?uuid"},
+		{Decimal, "github.com/shopspring/decimal.Decimal", decimalShape{}, "decimal"},
+		{Decimal, "github.com/shopspring/decimal.NullDecimal", nullDecimalShape{}, "?decimal"},
+		{ObjectID, "go.mongodb.org/mongo-driver/bson/primitive.ObjectID", objectIDShape{}, "objectid"},
+		{ObjectID, "go.mongodb.org/mongo-driver/v2/bson.ObjectID", objectIDShape{}, "objectid"},
+	} {
+		typ := reflect.TypeOf(c.v)
+		ext := c.ext.(named).as(t, c.key, typ)
+		if f, ok := ext.Describe(typ); !ok || f.Repr != c.want {
+			t.Errorf("%s: %+v %t", c.key, f, ok)
+		}
+		field := reflect.StructOf([]reflect.StructField{{Name: "V", Type: typ}})
+		model, err := model_reflect.New(reflect.New(field).Elem().Interface(), model_reflect.WithExtensions(ext))
+		if err != nil || model.String() != "{ V:"+c.want+" }" {
+			t.Errorf("%s: %s [%v]", c.key, model, err)
+		}
+	}
+}
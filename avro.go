@@ -0,0 +1,109 @@
+package model_reflect
+
+import (
+	"fmt"
+	"strings"
+)
+
+var avroScalars = map[string]string{
+	"bool":    "boolean",
+	"string":  "string",
+	"int":     "long",
+	"int8":    "int",
+	"int16":   "int",
+	"int32":   "int",
+	"int64":   "long",
+	"uint":    "long",
+	"uint8":   "int",
+	"uint16":  "int",
+	"uint32":  "long",
+	"uint64":  "long",
+	"float32": "float",
+	"float64": "double",
+}
+
+// Avro returns an Avro schema (.avsc) for the model. Named records are
+// defined on first use and referenced by name afterwards.
+func (m ModelInfo) Avro(namespace string) ([]byte, error) {
+	if m.root == nil || m.root.Kind == KindNil {
+		return nil, ErrNoType
+	}
+	if m.root.Kind != KindStruct || m.root.namedType() == "" {
+		return nil, fmt.Errorf("%w %s", ErrUnnamed, m)
+	}
+	g := &avroGen{defined: map[any]bool{}}
+	schema, err := g.record(m.root, messageName(m.root.namedType()))
+	if err != nil {
+		return nil, err
+	}
+	if namespace != "" {
+		schema["namespace"] = namespace
+	}
+	return marshalIndent(schema)
+}
+
+type avroGen struct {
+	defined map[any]bool
+}
+
+func (g *avroGen) record(n *Model, name string) (map[string]any, error) {
+	if n.namedType() != "" {
+		g.defined[n.typeKey()] = true
+	}
+	fields := []any{}
+	for _, f := range n.Fields {
+		typ, err := g.schema(f, f.Name)
+		if err != nil {
+			return nil, err
+		}
+		field := map[string]any{"name": f.wire(), "type": typ}
+		if f.Nullable {
+			field["type"] = []any{"null", typ}
+			field["default"] = nil
+		}
+		fields = append(fields, field)
+	}
+	return map[string]any{"type": "record", "name": name, "fields": fields}, nil
+}
+
+func (g *avroGen) schema(n *Model, name string) (any, error) {
+	switch n.Kind {
+	case KindScalar, KindLiteral:
+		if typ, ok := avroScalars[n.Repr]; ok {
+			return typ, nil
+		}
+	case KindOpaque:
+		if strings.Contains(n.Repr, "TextMarshaler") {
+			return "string", nil
+		}
+		return "bytes", nil
+	case KindSlice, KindArray:
+		if isBytes(n) {
+			return "bytes", nil
+		}
+		items, err := g.schema(n.Elem, name)
+		return map[string]any{"type": "array", "items": items}, err
+	case KindNullable:
+		return g.schema(n.Elem, name)
+	case KindMap:
+		if n.Key.Kind != KindScalar || n.Key.Repr != "string" {
+			return nil, fmt.Errorf("%w: map key %s of %s", ErrUnsupported, n.Key, name)
+		}
+		values, err := g.schema(n.Elem, name)
+		return map[string]any{"type": "map", "values": values}, err
+	case KindLoop, KindRef:
+		if typeName := n.namedType(); typeName != "" {
+			return messageName(typeName), nil
+		}
+	case KindStruct:
+		typeName := n.namedType()
+		if typeName == "" {
+			return g.record(n, messageName(name))
+		}
+		if g.defined[n.typeKey()] {
+			return messageName(typeName), nil
+		}
+		return g.record(n, messageName(typeName))
+	}
+	return nil, fmt.Errorf("%w: %s of %s", ErrUnsupported, n, name)
+}
@@ -0,0 +1,38 @@
+package model_reflect_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type avroEvent struct {
+	ID      int64             `json:"id"`
+	Tags    map[string]string `json:"tags"`
+	Parent  *avroEvent        `json:"parent"`
+	Payload []byte            `json:"payload"`
+}
+
+func TestAvro(t *testing.T) {
+	model, _ := model_reflect.New(avroEvent{})
+	b, err := model.Avro("com.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want any
+	_ = json.Unmarshal(b, &got)
+	_ = json.Unmarshal([]byte(`{
+		"type": "record", "name": "AvroEvent", "namespace": "com.example",
+		"fields": [
+			{"name": "id", "type": "long"},
+			{"name": "parent", "type": ["null", "AvroEvent"], "default": null},
+			{"name": "payload", "type": "bytes"},
+			{"name": "tags", "type": {"type": "map", "values": "string"}}
+		]
+	}`), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("avro:\n%s", b)
+	}
+}
@@ -0,0 +1,90 @@
+package model_reflect
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// NewAll reflects every value with the package defaults. Errors are
+// joined, each naming the index and type of its value.
+func NewAll(vs ...any) ([]ModelInfo, error) {
+	return newAll(nil, vs)
+}
+
+// NewAll is like the package-level NewAll using c.
+func (c Config) NewAll(vs ...any) ([]ModelInfo, error) {
+	return newAll([]Option{WithConfig(c)}, vs)
+}
+
+func newAll(opts []Option, vs []any) ([]ModelInfo, error) {
+	result := make([]ModelInfo, len(vs))
+	errs := []error{}
+	for i, v := range vs {
+		var err error
+		result[i], err = New(v, opts...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("model %d (%T): %w", i, v, err))
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// NewParallel reflects types with up to workers goroutines, or GOMAXPROCS
+// if workers is not positive, for registering many models at startup. The
+// result has one model per type in the same order. Errors are joined as by
+// NewAll; if ctx ends first its error is returned instead.
+func NewParallel(ctx context.Context, types []reflect.Type, workers int, opts ...Option) ([]ModelInfo, error) {
+	c := newConfig(opts...)
+	c.ctx = ctx
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	result := make([]ModelInfo, len(types))
+	errs := make([]error, len(types))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(types); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				m, err := c.newModel(types[i])
+				if err != nil {
+					err = fmt.Errorf("model %d (%s): %w", i, types[i], err)
+				}
+				result[i], errs[i] = m, err
+			}
+		}()
+	}
+	for i := range types {
+		if ctx.Err() != nil {
+			break
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return result, errors.Join(errs...)
+}
+
+// CombinedHash folds the hashes of models, in order, into one fingerprint
+// using the hasher of the first model.
+func CombinedHash(models ...ModelInfo) uint64 {
+	h := Hasher(DefaultHasher)
+	if len(models) > 0 {
+		h = models[0].hasher()
+	}
+	leaves := make([]byte, 0, 8*len(models))
+	for _, m := range models {
+		leaves = binary.LittleEndian.AppendUint64(leaves, m.Hash())
+	}
+	return sum64(h, leaves)
+}
@@ -0,0 +1,62 @@
+package model_reflect_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestNewAll(t *testing.T) {
+	models, err := model_reflect.NewAll(point{}, segment{})
+	if err != nil || len(models) != 2 || models[1].String() != "{ From:{ X:int, Y:int }, Path:[]{ X:int, Y:int }, To:{ X:int, Y:int } }" {
+		t.Errorf("new all: %v [%v]", models, err)
+	}
+	_, err = model_reflect.NewAll(point{}, (*testA)(nil))
+	if !errors.Is(err, model_reflect.ErrLoopDetected) || !strings.HasPrefix(err.Error(), "model 1 (*model_reflect_test.testA): ") {
+		t.Errorf("error: %v", err)
+	}
+	cbor := model_reflect.NewConfig(model_reflect.WithNameTags("cbor"))
+	if models, _ := cbor.NewAll(taggedStruct{}); models[0].String() != "{ CborName:int }" {
+		t.Errorf("config: %s", models[0])
+	}
+}
+
+func TestCombinedHash(t *testing.T) {
+	a, _ := model_reflect.NewAll(point{}, segment{})
+	b, _ := model_reflect.NewAll(point{}, segment{})
+	c, _ := model_reflect.NewAll(segment{}, point{})
+	if model_reflect.CombinedHash(a...) != model_reflect.CombinedHash(b...) ||
+		model_reflect.CombinedHash(a...) == model_reflect.CombinedHash(c...) ||
+		model_reflect.CombinedHash(a...) == model_reflect.CombinedHash(a[0]) {
+		t.Error("combined hash")
+	}
+}
+
+func TestNewParallel(t *testing.T) {
+	values := []any{point{}, segment{}, walkOrder{}, orderV1{}, orderV2{}, (*testA)(nil), taggedStruct{}}
+	types := make([]reflect.Type, len(values))
+	for i, v := range values {
+		types[i] = reflect.TypeOf(v)
+	}
+	want, wantErr := model_reflect.NewAll(values...)
+	for _, workers := range []int{0, 1, 3, 100} {
+		got, err := model_reflect.NewParallel(context.Background(), types, workers)
+		if len(got) != len(want) || err == nil || err.Error() != wantErr.Error() {
+			t.Fatalf("parallel %d: %v", workers, err)
+		}
+		for i := range got {
+			if !got[i].Equal(want[i]) {
+				t.Errorf("parallel %d: model %d %s", workers, i, got[i])
+			}
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := model_reflect.NewParallel(ctx, types, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("parallel: cancelled %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package model_reflect
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+type (
+	cacheKey struct {
+		t    reflect.Type
+		opts string
+	}
+
+	cacheEntry struct {
+		root   *Model
+		string string
+		errs   []error
+	}
+)
+
+var cache sync.Map
+
+// ClearCache drops all cached reflection results.
+func ClearCache() {
+	cache.Range(func(key, _ any) bool {
+		cache.Delete(key)
+		return true
+	})
+}
+
+// reflect returns the model tree of t, its canonical string and the
+// deduplicated errors found, walking the type only once per option set.
+func (c *config) reflect(t reflect.Type) (*Model, string, []error) {
+	if !c.cacheable() {
+		root, errs := c.build(t)
+		return root, formatPrefix(c.format) + root.String(), errs
+	}
+	key := cacheKey{t: t, opts: c.key()}
+	if e, ok := cache.Load(key); ok {
+		e := e.(*cacheEntry)
+		return e.root, e.string, slices.Clone(e.errs)
+	}
+	root, errs := c.build(t)
+	e := &cacheEntry{root: root, string: formatPrefix(c.format) + root.String(), errs: errs}
+	if c.cancelled() != nil {
+		return e.root, e.string, e.errs
+	}
+	cache.Store(key, e)
+	return e.root, e.string, slices.Clone(e.errs)
+}
+
+// build walks t into a model tree and returns it with the deduplicated,
+// sorted errors found.
+func (c *config) build(t reflect.Type) (*Model, []error) {
+	errs := []error{}
+	root := c.typeToNode(t, nil, "", &errs)
+	if c.value.IsValid() {
+		c.resolveValues(root, &errs)
+	}
+	if c.methods && t != nil {
+		root.Methods = c.methodNodes(baseType(t), &errs)
+	}
+	if c.typeRefs {
+		defineTypes(root)
+	}
+	if c.anchors {
+		anchorCycles(root)
+	}
+	errs = uniqueErrors(errs)
+	sortErrors(errs)
+	return root, errs
+}
+
+// cacheable reports whether every option can be represented in the cache
+// key; callbacks and values cannot.
+func (c *config) cacheable() bool {
+	return c.fieldFilter == nil && c.typeTransform == nil && !c.values
+}
+
+// key returns a string identifying every option that affects reflection.
+func (c *config) key() string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "%t,%t,%t,%t,%t,%d,%d,%t,%t,%t,%d,%t,%t,%t,%d,%t,%t,%t,%t,%t,%t,%t,%d|",
+		c.typeArgs, c.inline, c.ignoreMarkers, c.optionality, c.declarationOrder, c.embedding, c.maxDepth,
+		c.cycleRefs, c.typeRefs, c.typeNames, c.format, c.strict, c.funcs, c.chans, c.pointers, c.receivers,
+		c.methods, c.anchors, c.byteStrings, c.omitArrayLengths, c.definedNames,
+		c.sqlNulls, c.durations)
+	b.WriteString(strings.Join(c.nameTags, ","))
+	b.WriteString("|" + strings.Join(c.hashTags, ","))
+	for _, iface := range c.interfaces {
+		b.WriteString("|" + iface.PkgPath() + "." + iface.String())
+	}
+	b.WriteString("|")
+	for _, iface := range c.messages {
+		b.WriteString("," + iface.PkgPath() + "." + iface.String())
+	}
+	b.WriteString("|" + implementationsKey(c.impls))
+	b.WriteString("|" + typeNamesKey(c.wellKnown))
+	b.WriteString("|" + typeNamesKey(c.types))
+	b.WriteString("|" + extensionsKey(c.extensions))
+	return b.String()
+}
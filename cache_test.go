@@ -0,0 +1,24 @@
+package model_reflect_test
+
+import (
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestCache(t *testing.T) {
+	model_reflect.ClearCache()
+	a, _ := model_reflect.New(taggedStruct{})
+	b, _ := model_reflect.New(taggedStruct{})
+	if a.Model() != b.Model() {
+		t.Error("second call was not cached")
+	}
+	c, _ := model_reflect.New(taggedStruct{}, model_reflect.WithNameTags("cbor"))
+	if c.Model() == a.Model() || c.String() == a.String() {
+		t.Error("options are not part of the cache key")
+	}
+	model_reflect.ClearCache()
+	if d, _ := model_reflect.New(taggedStruct{}); d.Model() == a.Model() || d.String() != a.String() {
+		t.Error("cache was not cleared")
+	}
+}
@@ -0,0 +1,131 @@
+package model_reflect
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var cddlScalars = map[string]string{
+	"bool":    "bool",
+	"string":  "tstr",
+	"int":     "int",
+	"int8":    "int",
+	"int16":   "int",
+	"int32":   "int",
+	"int64":   "int",
+	"uint":    "uint",
+	"uint8":   "uint",
+	"uint16":  "uint",
+	"uint32":  "uint",
+	"uint64":  "uint",
+	"uintptr": "uint",
+	"float32": "float32",
+	"float64": "float64",
+}
+
+var cddlIdent = regexp.MustCompile(`^[A-Za-z@_$][A-Za-z0-9@_$.-]*$`)
+
+// CDDL returns a CDDL (RFC 8610) description of the model's CBOR layout.
+// Every named struct type becomes a rule, the model's own type first.
+func (m ModelInfo) CDDL() (string, error) {
+	if m.root == nil || m.root.Kind == KindNil {
+		return "", ErrNoType
+	}
+	g := &cddlGen{done: map[any]bool{}}
+	if m.root.Kind != KindStruct || m.root.namedType() == "" {
+		return "model = " + g.typ(m.root) + "\n", nil
+	}
+	b := &strings.Builder{}
+	g.queue = append(g.queue, m.root)
+	for len(g.queue) > 0 {
+		n := g.queue[0]
+		g.queue = g.queue[1:]
+		if g.done[n.typeKey()] {
+			continue
+		}
+		g.done[n.typeKey()] = true
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "%s = %s\n", n.namedType(), g.group(n, ""))
+	}
+	return b.String(), nil
+}
+
+type cddlGen struct {
+	done  map[any]bool
+	queue []*Model
+}
+
+func (g *cddlGen) group(n *Model, indent string) string {
+	if len(n.Fields) == 0 {
+		return "{}"
+	}
+	b := &strings.Builder{}
+	b.WriteString("{\n")
+	for i, f := range n.Fields {
+		key := f.wire()
+		if !cddlIdent.MatchString(key) {
+			key = strconv.Quote(key)
+		}
+		fmt.Fprintf(b, "%s  %s: %s", indent, key, g.field(f, indent+"  "))
+		if i < len(n.Fields)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	return b.String() + indent + "}"
+}
+
+func (g *cddlGen) field(n *Model, indent string) string {
+	typ := g.typeIndent(n, indent)
+	if n.Nullable {
+		return typ + " / nil"
+	}
+	return typ
+}
+
+func (g *cddlGen) typ(n *Model) string {
+	return g.typeIndent(n, "")
+}
+
+func (g *cddlGen) typeIndent(n *Model, indent string) string {
+	switch n.Kind {
+	case KindScalar, KindLiteral:
+		if typ, ok := cddlScalars[n.Repr]; ok {
+			return typ
+		}
+	case KindOpaque:
+		if strings.Contains(n.Repr, "BinaryMarshaler") {
+			return "bstr"
+		}
+		if strings.Contains(n.Repr, "TextMarshaler") {
+			return "tstr"
+		}
+	case KindSlice:
+		if isBytes(n) {
+			return "bstr"
+		}
+		return "[* " + g.field(n.Elem, indent) + "]"
+	case KindArray:
+		return fmt.Sprintf("[%d*%d %s]", n.Len, n.Len, g.field(n.Elem, indent))
+	case KindNullable:
+		return g.typeIndent(n.Elem, indent)
+	case KindMap:
+		return "{ * " + g.field(n.Key, indent) + " => " + g.field(n.Elem, indent) + " }"
+	case KindLoop, KindRef:
+		if name := n.namedType(); name != "" {
+			return name
+		}
+	case KindStruct:
+		name := n.namedType()
+		if name == "" {
+			return g.group(n, indent)
+		}
+		g.queue = append(g.queue, n)
+		return name
+	}
+	return "any"
+}
@@ -0,0 +1,48 @@
+package model_reflect_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type cddlPoint struct {
+	X, Y float64
+}
+
+type cddlShape struct {
+	Name   string            `cbor:"name"`
+	Points []cddlPoint       `cbor:"points"`
+	Hash   [4]byte           `cbor:"hash"`
+	Attrs  map[string]*int   `cbor:"attrs"`
+	At     time.Time         `cbor:"created-at"`
+	Extra  struct{ On bool } `cbor:"extra"`
+}
+
+func TestCDDL(t *testing.T) {
+	model, _ := model_reflect.New(cddlShape{})
+	s, err := model.CDDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `cddlShape = {
+  attrs: { * tstr => int / nil },
+  created-at: bstr,
+  extra: {
+    On: bool
+  },
+  hash: [4*4 uint],
+  name: tstr,
+  points: [* cddlPoint]
+}
+
+cddlPoint = {
+  X: float64,
+  Y: float64
+}
+`
+	if s != want {
+		t.Errorf("cddl:\n%s\nwant:\n%s", s, want)
+	}
+}
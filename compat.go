@@ -0,0 +1,145 @@
+package model_reflect
+
+type (
+	// CompatEntry classifies a single change between two models.
+	// Backward is set when payloads written with the old model can no longer
+	// be decoded by the new model, Forward when payloads written with the new
+	// model can no longer be decoded by the old model.
+	CompatEntry struct {
+		Change
+		Backward bool
+		Forward  bool
+	}
+
+	// Report is the wire compatibility report between two models.
+	Report struct {
+		Entries []CompatEntry
+	}
+)
+
+// Compatibility classifies every difference between oldModel and newModel.
+//
+// Fields missing from a payload decode to their zero value, so added and
+// removed fields are not breaking. Renamed fields and changed types are
+// breaking in both directions, except for numeric widening which remains
+// readable by the wider side. A field made optional breaks old readers,
+// which may receive payloads without it; a field made required breaks new
+// readers of old payloads. Changed tags are reported without breaking
+// either direction.
+func Compatibility(oldModel, newModel ModelInfo) Report {
+	r := Report{}
+	for _, c := range oldModel.Diff(newModel).Changes {
+		e := CompatEntry{Change: c}
+		switch c.Kind {
+		case FieldRenamed:
+			e.Backward, e.Forward = true, true
+		case TypeChanged:
+			e.Backward = !widens(c.Old, c.New)
+			e.Forward = !widens(c.New, c.Old)
+		case OptionalityChanged:
+			e.Forward = becameOptional(c)
+			e.Backward = !e.Forward
+		}
+		r.Entries = append(r.Entries, e)
+	}
+	return r
+}
+
+// BackwardCompatible reports whether old payloads decode with the new model.
+func (r Report) BackwardCompatible() bool {
+	for _, e := range r.Entries {
+		if e.Backward {
+			return false
+		}
+	}
+	return true
+}
+
+// ForwardCompatible reports whether new payloads decode with the old model.
+func (r Report) ForwardCompatible() bool {
+	for _, e := range r.Entries {
+		if e.Forward {
+			return false
+		}
+	}
+	return true
+}
+
+// Breaking returns the entries that break either direction.
+func (r Report) Breaking() []CompatEntry {
+	result := []CompatEntry{}
+	for _, e := range r.Entries {
+		if e.Backward || e.Forward {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// IsSubsetOf reports whether every field of m, including the fields of
+// nested structs, exists in other with a compatible type, such as a read
+// model that only references fields of a write model. Types are compatible
+// when they are equal, when they are structs in the same relation, when
+// their elements are compatible or when other's numeric type widens to m's.
+func (m ModelInfo) IsSubsetOf(other ModelInfo) bool {
+	return isSubset(m.root, other.root)
+}
+
+func isSubset(a, b *Model) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	switch {
+	case a.Kind == KindStruct && b.Kind == KindStruct:
+		fields := map[string]*Model{}
+		for _, f := range b.Fields {
+			fields[fieldKey(f)] = f
+		}
+		for _, f := range a.Fields {
+			if !isSubset(f, fields[fieldKey(f)]) {
+				return false
+			}
+		}
+		return true
+	case a.Kind == KindSlice && b.Kind == KindSlice,
+		a.Kind == KindArray && b.Kind == KindArray && a.Len == b.Len,
+		a.Kind == KindMap && b.Kind == KindMap && a.Key.String() == b.Key.String():
+		return isSubset(a.Elem, b.Elem)
+	case a.Kind == KindScalar && b.Kind == KindScalar && widens(b.Repr, a.Repr):
+		return true
+	}
+	return a.String() == b.String()
+}
+
+type numericKind struct {
+	family string
+	bits   int
+}
+
+var numericKinds = map[string]numericKind{
+	"int8":    {"int", 8},
+	"int16":   {"int", 16},
+	"int32":   {"int", 32},
+	"int64":   {"int", 64},
+	"int":     {"int", 64},
+	"uint8":   {"uint", 8},
+	"uint16":  {"uint", 16},
+	"uint32":  {"uint", 32},
+	"uint64":  {"uint", 64},
+	"uint":    {"uint", 64},
+	"float32": {"float", 32},
+	"float64": {"float", 64},
+}
+
+// widens reports whether every value of type from fits into type to.
+func widens(from, to string) bool {
+	f, ok := numericKinds[from]
+	if !ok {
+		return false
+	}
+	t, ok := numericKinds[to]
+	if !ok {
+		return false
+	}
+	return f.family == t.family && f.bits <= t.bits
+}
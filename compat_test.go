@@ -0,0 +1,77 @@
+package model_reflect_test
+
+import (
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type compatV1 struct {
+	Count int32
+	Name  string
+}
+
+type compatV2 struct {
+	Count int64
+	Name  string
+	Extra bool
+}
+
+func TestCompatibility(t *testing.T) {
+	v1, _ := model_reflect.New(compatV1{})
+	v2, _ := model_reflect.New(compatV2{})
+	r := model_reflect.Compatibility(v1, v2)
+	if !r.BackwardCompatible() || r.ForwardCompatible() {
+		t.Errorf("widening: %+v", r.Entries)
+	}
+	if b := r.Breaking(); len(b) != 1 || b[0].Path != "Count" {
+		t.Errorf("breaking: %+v", b)
+	}
+	r = model_reflect.Compatibility(v2, v1)
+	if r.BackwardCompatible() || !r.ForwardCompatible() {
+		t.Errorf("narrowing: %+v", r.Entries)
+	}
+}
+
+func TestCompatibilityOptionality(t *testing.T) {
+	required, _ := model_reflect.New(contactRequired{}, model_reflect.WithOptionality())
+	optional, _ := model_reflect.New(contactOptional{}, model_reflect.WithOptionality())
+	r := model_reflect.Compatibility(required, optional)
+	if len(r.Entries) != 2 || !r.BackwardCompatible() || r.ForwardCompatible() {
+		t.Errorf("made optional: %+v", r.Entries)
+	}
+	r = model_reflect.Compatibility(optional, required)
+	if len(r.Entries) != 2 || r.BackwardCompatible() || !r.ForwardCompatible() {
+		t.Errorf("made required: %+v", r.Entries)
+	}
+}
+
+type compatRead struct {
+	Count int64
+	Lines []struct{ SKU string }
+}
+
+type compatWrite struct {
+	Count int32
+	Name  string
+	Lines []struct {
+		SKU string
+		Qty int
+	}
+}
+
+func TestIsSubsetOf(t *testing.T) {
+	read, _ := model_reflect.New(compatRead{})
+	write, _ := model_reflect.New(compatWrite{})
+	if !read.IsSubsetOf(write) || write.IsSubsetOf(read) {
+		t.Error("subset: read/write")
+	}
+	if !read.IsSubsetOf(read) {
+		t.Error("subset: reflexive")
+	}
+	v1, _ := model_reflect.New(compatV1{})
+	v2, _ := model_reflect.New(compatV2{})
+	if v2.IsSubsetOf(v1) || !v1.IsSubsetOf(v1) {
+		t.Error("subset: v1/v2")
+	}
+}
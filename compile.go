@@ -0,0 +1,75 @@
+package model_reflect
+
+import (
+	"reflect"
+	"sync"
+)
+
+// CompiledModel is a model reflected once for repeated hashing and export,
+// for servers that fingerprint many namespaces of the same types. It is
+// safe for concurrent use.
+type CompiledModel struct {
+	info  ModelInfo
+	bytes []byte
+	// namespaces memoizes HashNamespace by namespace.
+	namespaces sync.Map
+}
+
+// Compile reflects t with opts. Unlike New it returns no model when
+// reflection reports errors.
+func Compile(t reflect.Type, opts ...Option) (*CompiledModel, error) {
+	info, err := NewFromType(t, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &CompiledModel{info: info, bytes: []byte(info.string)}, nil
+}
+
+// Info returns the reflected model, for example to export it with
+// JSONSchema or Proto.
+func (c *CompiledModel) Info() ModelInfo {
+	return c.info
+}
+
+// String returns the canonical representation of the model.
+func (c *CompiledModel) String() string {
+	return c.info.string
+}
+
+// Hash returns the hash of the model with the hasher it was compiled with.
+func (c *CompiledModel) Hash() uint64 {
+	return c.info.Hash()
+}
+
+// HashWith returns the hash of the model with h.
+func (c *CompiledModel) HashWith(h Hasher) uint64 {
+	return sum64(h, c.bytes)
+}
+
+// HashNamespace returns the hash of the model with the hasher it was
+// compiled with, salted for namespace. A NamespaceHasher, such as HashInfo
+// or the KDF hashers, is salted by its Namespace method; any other hasher,
+// such as SHA256 or HMACHasher, hashes the SHA-256 of namespace followed by
+// the model. Results are memoized per namespace.
+func (c *CompiledModel) HashNamespace(namespace string) uint64 {
+	if hash, ok := c.namespaces.Load(namespace); ok {
+		return hash.(uint64)
+	}
+	hash := c.HashWith(namespaced(c.info.hasher(), namespace))
+	c.namespaces.Store(namespace, hash)
+	return hash
+}
+
+// namespaced returns h salted for namespace.
+func namespaced(h Hasher, namespace string) Hasher {
+	if info, ok := h.(*HashInfo); ok && info == nil {
+		h = DefaultHasher
+	}
+	if h, ok := h.(NamespaceHasher); ok {
+		return h.Namespace(namespace)
+	}
+	salt := namespaceSalt(namespace)
+	return HasherFunc(func(model []byte, size int) []byte {
+		return h.Sum(append(append([]byte(nil), salt...), model...), size)
+	})
+}
@@ -0,0 +1,50 @@
+package model_reflect_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestCompile(t *testing.T) {
+	compiled, err := model_reflect.Compile(reflect.TypeOf(point{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := model_reflect.New(point{})
+	if compiled.String() != want.String() || compiled.Hash() != want.Hash() || !compiled.Info().Equal(want) {
+		t.Errorf("compile: %s", compiled)
+	}
+	sha, _ := model_reflect.New(point{}, model_reflect.WithHasher(model_reflect.SHA256))
+	if compiled.HashWith(model_reflect.SHA256) != sha.Hash() {
+		t.Error("compile: hash with")
+	}
+	for _, ns := range []string{"orders", "billing"} {
+		salted, _ := model_reflect.New(point{}, model_reflect.WithHasher(model_reflect.DefaultHasher.WithNamespace(ns)))
+		if compiled.HashNamespace(ns) != salted.Hash() || compiled.HashNamespace(ns) != salted.Hash() {
+			t.Errorf("compile: namespace %s", ns)
+		}
+	}
+	params := model_reflect.HashInfo{Salt: []byte("salt"), Time: 1, Memory: 8, Threads: 1}
+	pointer, _ := model_reflect.Compile(reflect.TypeOf(point{}), model_reflect.WithHasher(&params))
+	salted, _ := model_reflect.New(point{}, model_reflect.WithHasher(params.WithNamespace("orders")))
+	if pointer.HashNamespace("orders") != salted.Hash() {
+		t.Error("compile: namespace of *HashInfo")
+	}
+	digest, _ := model_reflect.Compile(reflect.TypeOf(point{}), model_reflect.WithHasher(model_reflect.SHA256))
+	plain, _ := model_reflect.Compile(reflect.TypeOf(point{}))
+	if digest.HashNamespace("orders") == digest.HashNamespace("billing") || digest.HashNamespace("orders") == digest.Hash() ||
+		digest.HashNamespace("orders") == plain.HashNamespace("orders") {
+		t.Error("compile: namespace of SHA256")
+	}
+	scrypt := model_reflect.ScryptHasher{Salt: []byte("salt"), N: 16, R: 1, P: 1}
+	kdf, _ := model_reflect.Compile(reflect.TypeOf(point{}), model_reflect.WithHasher(scrypt))
+	if kdf.HashNamespace("orders") != kdf.HashWith(scrypt.Namespace("orders")) || kdf.HashNamespace("orders") == kdf.Hash() {
+		t.Error("compile: namespace of ScryptHasher")
+	}
+	if _, err := model_reflect.Compile(reflect.TypeOf(pathErrorStruct{})); !errors.Is(err, model_reflect.ErrEmptyStruct) {
+		t.Errorf("compile: errors %v", err)
+	}
+}
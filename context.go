@@ -0,0 +1,30 @@
+package model_reflect
+
+import (
+	"context"
+	"reflect"
+)
+
+// NewContext is like New but checks ctx between recursion steps and returns
+// its error if it is cancelled or its deadline passes before the model is
+// complete. Use it when reflecting user-influenced or very large type graphs
+// in request handlers.
+func NewContext(ctx context.Context, v any, opts ...Option) (ModelInfo, error) {
+	c := newConfig(opts...)
+	c.ctx = ctx
+	c.setValue(reflect.ValueOf(v))
+	return c.newModel(reflect.TypeOf(v))
+}
+
+// NewContext is a shorthand for NewContext(ctx, v, WithConfig(c)).
+func (c Config) NewContext(ctx context.Context, v any) (ModelInfo, error) {
+	return NewContext(ctx, v, WithConfig(c))
+}
+
+// cancelled returns the error of the context passed to NewContext, if any.
+func (c *config) cancelled() error {
+	if c.ctx == nil {
+		return nil
+	}
+	return c.ctx.Err()
+}
@@ -0,0 +1,57 @@
+package model_reflect_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-modern/model_reflect"
+)
+
+// countdownContext is cancelled after its Err method was called n times.
+type countdownContext struct {
+	context.Context
+	n int
+}
+
+func (c *countdownContext) Err() error {
+	if c.n--; c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+type contextDeep struct {
+	A, B, C, D struct{ X, Y, Z struct{ V int } }
+}
+
+func TestNewContext(t *testing.T) {
+	want, _ := model_reflect.New(contextDeep{})
+	got, err := model_reflect.NewContext(context.Background(), contextDeep{})
+	if err != nil || got.String() != want.String() {
+		t.Fatalf("background: %s [%v]", got, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := model_reflect.NewContext(ctx, contextDeep{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled: %v", err)
+	}
+
+	ctx = &countdownContext{Context: context.Background(), n: 5}
+	opts := model_reflect.WithMaxDepth(100)
+	if _, err := model_reflect.NewContext(ctx, contextDeep{}, opts); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled during reflection: %v", err)
+	}
+	if got, err := model_reflect.NewConfig(opts).NewContext(context.Background(), contextDeep{}); err != nil ||
+		got.String() != want.String() {
+		t.Errorf("partial model cached: %s [%v]", got, err)
+	}
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if _, err := model_reflect.NewContext(ctx, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("deadline: %v", err)
+	}
+}
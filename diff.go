@@ -0,0 +1,301 @@
+package model_reflect
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+type (
+	// ChangeKind classifies a single difference between two models.
+	ChangeKind uint8
+
+	// Change describes a single difference between two models. Path is the
+	// field path in the new model, or in the old model for removed fields.
+	Change struct {
+		Kind    ChangeKind
+		Path    string
+		OldPath string
+		Old     string
+		New     string
+	}
+
+	// ModelDiff is the structured difference between two models.
+	ModelDiff struct {
+		// Name is the type name of the new model's root, or of the old
+		// model's if the new one is unnamed.
+		Name    string
+		Changes []Change
+	}
+)
+
+const (
+	// FieldAdded is reported for fields only present in the new model.
+	FieldAdded ChangeKind = iota
+	// FieldRemoved is reported for fields only present in the old model.
+	FieldRemoved
+	// FieldRenamed is reported for fields whose resolved name changed.
+	FieldRenamed
+	// TypeChanged is reported for fields whose type changed.
+	TypeChanged
+	// OptionalityChanged is reported for fields that became optional or
+	// required. Old and New are the field renderings, as in Name?:string.
+	OptionalityChanged
+	// AnnotationChanged is reported for fields whose struct tags recorded
+	// with WithTags changed. Old and New are the field renderings.
+	AnnotationChanged
+)
+
+// String returns the name of the change kind.
+func (k ChangeKind) String() string {
+	switch k {
+	case FieldAdded:
+		return "added"
+	case FieldRemoved:
+		return "removed"
+	case FieldRenamed:
+		return "renamed"
+	case TypeChanged:
+		return "type changed"
+	case OptionalityChanged:
+		return "optionality changed"
+	case AnnotationChanged:
+		return "annotation changed"
+	default:
+		return "unknown"
+	}
+}
+
+// Empty reports whether the diff contains no changes.
+func (d ModelDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// Diff returns the structured difference from m to other.
+func (m ModelInfo) Diff(other ModelInfo) ModelDiff {
+	d := ModelDiff{Name: rootName(other.root)}
+	if d.Name == "" {
+		d.Name = rootName(m.root)
+	}
+	d.node("", m.root, other.root)
+	sort.SliceStable(d.Changes, func(i, j int) bool {
+		return d.Changes[i].Path < d.Changes[j].Path
+	})
+	return d
+}
+
+// Changelog returns one sentence per change for release notes, such as
+// "field Order.Status changed from int to string".
+func (d ModelDiff) Changelog() []string {
+	result := make([]string, 0, len(d.Changes))
+	for _, c := range d.Changes {
+		path, oldPath := d.qualify(c.Path), d.qualify(c.OldPath)
+		switch c.Kind {
+		case FieldAdded:
+			result = append(result, "field "+path+" added")
+		case FieldRemoved:
+			result = append(result, "field "+oldPath+" removed")
+		case FieldRenamed:
+			result = append(result, "field "+oldPath+" renamed to "+path)
+		case TypeChanged:
+			subject := "field " + path
+			switch {
+			case c.Path == "" && d.Name == "":
+				subject = "model"
+			case c.Path == "":
+				subject = "type " + path
+			}
+			result = append(result, subject+" changed from "+c.Old+" to "+c.New)
+		case OptionalityChanged:
+			if becameOptional(c) {
+				result = append(result, "field "+path+" made optional")
+			} else {
+				result = append(result, "field "+path+" made required")
+			}
+		case AnnotationChanged:
+			result = append(result, "field "+path+" tags changed from "+c.Old+" to "+c.New)
+		}
+	}
+	return result
+}
+
+// qualify prefixes a change path with the diffed type name.
+func (d ModelDiff) qualify(path string) string {
+	switch {
+	case d.Name == "":
+		return path
+	case path == "" || strings.HasPrefix(path, "["):
+		return d.Name + path
+	}
+	return d.Name + "." + path
+}
+
+// becameOptional reports whether an OptionalityChanged change made its
+// field optional.
+func becameOptional(c Change) bool {
+	name := c.Path[strings.LastIndexAny(c.Path, ".]")+1:]
+	return strings.HasPrefix(c.New, name+"?:")
+}
+
+func rootName(n *Model) string {
+	if n == nil || n.Type == nil || n.Type.PkgPath() == "" {
+		return ""
+	}
+	return n.Type.Name()
+}
+
+// Compare reflects a and b with opts and returns the difference from a to
+// b. The diff is computed even when reflection reports errors, which are
+// returned joined.
+func Compare(a, b any, opts ...Option) (ModelDiff, error) {
+	ma, errA := New(a, opts...)
+	mb, errB := New(b, opts...)
+	return ma.Diff(mb), errors.Join(errA, errB)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func fieldKey(n *Model) string {
+	if n.Embedded {
+		return "." + n.Name
+	}
+	return n.Name
+}
+
+func (d *ModelDiff) node(path string, a, b *Model) {
+	if a == nil || b == nil {
+		if a != b {
+			d.Changes = append(d.Changes, Change{
+				Kind: TypeChanged, Path: path, OldPath: path, Old: a.String(), New: b.String(),
+			})
+		}
+		return
+	}
+	as, bs := a.String(), b.String()
+	if as == bs {
+		return
+	}
+	switch {
+	case a.Kind == KindStruct && b.Kind == KindStruct:
+		d.fields(path, a.Fields, b.Fields)
+	case a.Kind == KindSlice && b.Kind == KindSlice,
+		a.Kind == KindArray && b.Kind == KindArray && a.Len == b.Len,
+		a.Kind == KindMap && b.Kind == KindMap && a.Key.String() == b.Key.String():
+		d.node(path+"[]", a.Elem, b.Elem)
+	default:
+		d.Changes = append(d.Changes, Change{
+			Kind: TypeChanged, Path: path, OldPath: path, Old: as, New: bs,
+		})
+	}
+}
+
+// field compares the renderings of two fields, which unlike their models
+// include the optional marker and annotation, and then their models.
+func (d *ModelDiff) field(path string, a, b *Model) {
+	change := func(kind ChangeKind) {
+		d.Changes = append(d.Changes, Change{
+			Kind: kind, Path: path, OldPath: path,
+			Old: string(fieldLeaf(a)), New: string(fieldLeaf(b)),
+		})
+	}
+	if a.Optional != b.Optional {
+		change(OptionalityChanged)
+	}
+	if a.Annotation != b.Annotation {
+		change(AnnotationChanged)
+	}
+	d.node(path, a, b)
+}
+
+func (d *ModelDiff) fields(path string, a, b []*Model) {
+	oldFields := map[string]*Model{}
+	for _, f := range a {
+		oldFields[fieldKey(f)] = f
+	}
+	newFields := map[string]*Model{}
+	for _, f := range b {
+		newFields[fieldKey(f)] = f
+	}
+	removed := []*Model{}
+	for _, f := range a {
+		if nf, ok := newFields[fieldKey(f)]; ok {
+			d.field(joinPath(path, f.Name), f, nf)
+		} else {
+			removed = append(removed, f)
+		}
+	}
+	added := []*Model{}
+	for _, f := range b {
+		if _, ok := oldFields[fieldKey(f)]; !ok {
+			added = append(added, f)
+		}
+	}
+
+	renamed := map[*Model]*Model{}
+	for _, r := range removed {
+		for _, n := range added {
+			if r.GoName != "" && r.GoName == n.GoName && r.Embedded == n.Embedded {
+				renamed[r] = n
+				break
+			}
+		}
+	}
+	byType := func(list []*Model, s string) (match *Model, count int) {
+		for _, n := range list {
+			if n.String() == s {
+				match = n
+				count++
+			}
+		}
+		return
+	}
+	for _, r := range removed {
+		if renamed[r] != nil {
+			continue
+		}
+		s := r.String()
+		n, cn := byType(added, s)
+		if _, cr := byType(removed, s); cn == 1 && cr == 1 && !isRenameTarget(renamed, n) {
+			renamed[r] = n
+		}
+	}
+
+	for _, r := range removed {
+		oldPath := joinPath(path, r.Name)
+		n := renamed[r]
+		if n == nil {
+			d.Changes = append(d.Changes, Change{
+				Kind: FieldRemoved, Path: oldPath, OldPath: oldPath, Old: r.String(),
+			})
+			continue
+		}
+		newPath := joinPath(path, n.Name)
+		d.Changes = append(d.Changes, Change{
+			Kind: FieldRenamed, Path: newPath, OldPath: oldPath, Old: r.String(), New: n.String(),
+		})
+		d.field(newPath, r, n)
+	}
+	for _, n := range added {
+		if isRenameTarget(renamed, n) {
+			continue
+		}
+		newPath := joinPath(path, n.Name)
+		d.Changes = append(d.Changes, Change{
+			Kind: FieldAdded, Path: newPath, New: n.String(),
+		})
+	}
+}
+
+func isRenameTarget(renamed map[*Model]*Model, n *Model) bool {
+	for _, v := range renamed {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
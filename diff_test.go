@@ -0,0 +1,144 @@
+package model_reflect_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type orderV1 struct {
+	ID     int
+	Status int
+	Items  []struct {
+		Name  string
+		Price float32
+	}
+	Note string `json:"note"`
+	Old  bool
+}
+
+type orderV2 struct {
+	ID     int
+	Status string
+	Items  []struct {
+		Name  string
+		Price float64
+	}
+	Note  string `json:"comment"`
+	Added uint8
+}
+
+func TestDiff(t *testing.T) {
+	a, _ := model_reflect.New(orderV1{})
+	b, _ := model_reflect.New(orderV2{})
+	want := []model_reflect.Change{
+		{Kind: model_reflect.FieldAdded, Path: "Added", New: "uint8"},
+		{Kind: model_reflect.FieldRenamed, Path: "Comment", OldPath: "Note", Old: "string", New: "string"},
+		{Kind: model_reflect.TypeChanged, Path: "Items[].Price", OldPath: "Items[].Price", Old: "float32", New: "float64"},
+		{Kind: model_reflect.FieldRemoved, Path: "Old", OldPath: "Old", Old: "bool"},
+		{Kind: model_reflect.TypeChanged, Path: "Status", OldPath: "Status", Old: "int", New: "string"},
+	}
+	if d := a.Diff(b); !reflect.DeepEqual(d.Changes, want) {
+		t.Errorf("diff:\n%+v\nwant:\n%+v", d.Changes, want)
+	}
+	if d := a.Diff(a); !d.Empty() {
+		t.Errorf("self diff: %+v", d.Changes)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	d, err := model_reflect.Compare(orderV1{}, orderV2{})
+	v1, _ := model_reflect.New(orderV1{})
+	v2, _ := model_reflect.New(orderV2{})
+	if err != nil || len(d.Changes) == 0 || len(d.Changes) != len(v1.Diff(v2).Changes) {
+		t.Errorf("compare: %v [%v]", d, err)
+	}
+	if d, err := model_reflect.Compare(orderV1{}, &orderV1{}); err != nil || !d.Empty() {
+		t.Errorf("same model: %v [%v]", d, err)
+	}
+	if _, err := model_reflect.Compare((*testA)(nil), orderV1{}); !errors.Is(err, model_reflect.ErrLoopDetected) {
+		t.Errorf("error: %v", err)
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	a, _ := model_reflect.New(orderV1{})
+	b, _ := model_reflect.New(orderV2{})
+	want := []string{
+		"field orderV2.Added added",
+		"field orderV2.Note renamed to orderV2.Comment",
+		"field orderV2.Items[].Price changed from float32 to float64",
+		"field orderV2.Old removed",
+		"field orderV2.Status changed from int to string",
+	}
+	if got := a.Diff(b).Changelog(); !reflect.DeepEqual(got, want) {
+		t.Errorf("changelog: %q", got)
+	}
+	x, _ := model_reflect.New(0)
+	y, _ := model_reflect.New("")
+	if got := x.Diff(y).Changelog(); !reflect.DeepEqual(got, []string{"model changed from int to string"}) {
+		t.Errorf("changelog: %q", got)
+	}
+}
+
+type (
+	contactRequired struct {
+		Email string `json:"email"`
+		Lines []struct {
+			Qty int `json:"qty"`
+		}
+	}
+	contactOptional struct {
+		Email string `json:"email,omitempty"`
+		Lines []struct {
+			Qty int `json:"qty,omitempty"`
+		}
+	}
+)
+
+func TestDiffOptionality(t *testing.T) {
+	required, _ := model_reflect.New(contactRequired{}, model_reflect.WithOptionality())
+	optional, _ := model_reflect.New(contactOptional{}, model_reflect.WithOptionality())
+	want := []model_reflect.Change{
+		{Kind: model_reflect.OptionalityChanged, Path: "Email", OldPath: "Email", Old: "Email:string", New: "Email?:string"},
+		{Kind: model_reflect.OptionalityChanged, Path: "Lines[].Qty", OldPath: "Lines[].Qty", Old: "Qty:int", New: "Qty?:int"},
+	}
+	d := required.Diff(optional)
+	if !reflect.DeepEqual(d.Changes, want) {
+		t.Errorf("diff:\n%+v\nwant:\n%+v", d.Changes, want)
+	}
+	log := []string{"field contactOptional.Email made optional", "field contactOptional.Lines[].Qty made optional"}
+	if got := d.Changelog(); !reflect.DeepEqual(got, log) {
+		t.Errorf("changelog: %q", got)
+	}
+	log = []string{"field contactRequired.Email made required", "field contactRequired.Lines[].Qty made required"}
+	if got := optional.Diff(required).Changelog(); !reflect.DeepEqual(got, log) {
+		t.Errorf("changelog: %q", got)
+	}
+}
+
+func TestDiffAnnotation(t *testing.T) {
+	type limitV1 struct {
+		Qty int `validate:"max=10"`
+	}
+	type limitV2 struct {
+		Qty int `validate:"max=5"`
+	}
+	a, _ := model_reflect.New(limitV1{}, model_reflect.WithTags("validate"))
+	b, _ := model_reflect.New(limitV2{}, model_reflect.WithTags("validate"))
+	want := []model_reflect.Change{{
+		Kind: model_reflect.AnnotationChanged, Path: "Qty", OldPath: "Qty",
+		Old: "Qty:int`validate:\"max=10\"`", New: "Qty:int`validate:\"max=5\"`",
+	}}
+	if d := a.Diff(b); !reflect.DeepEqual(d.Changes, want) {
+		t.Errorf("diff:\n%+v\nwant:\n%+v", d.Changes, want)
+	}
+	if got := model_reflect.RecommendBump(a, b); got != model_reflect.BumpMajor {
+		t.Errorf("bump: %s", got)
+	}
+	if r := model_reflect.Compatibility(a, b); len(r.Entries) != 1 || len(r.Breaking()) != 0 {
+		t.Errorf("compatibility: %+v", r.Entries)
+	}
+}
@@ -0,0 +1,102 @@
+package model_reflect
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+type (
+	// LoopError reports a type that contains itself. It matches
+	// ErrLoopDetected with errors.Is.
+	LoopError struct {
+		Type reflect.Type
+	}
+
+	// DuplicateError reports Count fields resolving to Name at the same
+	// embedding level of Type. It matches ErrDuplicate with errors.Is.
+	DuplicateError struct {
+		Type  reflect.Type
+		Level int
+		Name  string
+		Count int
+	}
+
+	// EmptyStructError reports a struct without exported fields. It matches
+	// ErrEmptyStruct with errors.Is.
+	EmptyStructError struct {
+		Type reflect.Type
+	}
+)
+
+func (e LoopError) Error() string {
+	return fmt.Sprintf("%s in %s", ErrLoopDetected, e.Type)
+}
+
+// Is reports whether target is ErrLoopDetected.
+func (e LoopError) Is(target error) bool {
+	return target == ErrLoopDetected
+}
+
+func (e DuplicateError) Error() string {
+	return fmt.Sprintf("type %s (embed level %d): %s [%d]%s", e.Type, e.Level, ErrDuplicate, e.Count, e.Name)
+}
+
+// Is reports whether target is ErrDuplicate.
+func (e DuplicateError) Is(target error) bool {
+	return target == ErrDuplicate
+}
+
+func (e EmptyStructError) Error() string {
+	return fmt.Sprintf("%s %s", ErrEmptyStruct, e.Type)
+}
+
+// Is reports whether target is ErrEmptyStruct.
+func (e EmptyStructError) Is(target error) bool {
+	return target == ErrEmptyStruct
+}
+
+// sortErrors orders errors by path, then message, so that Errs does not
+// depend on traversal order.
+func sortErrors(errs []error) {
+	path := func(err error) string {
+		var pathErr *PathError
+		if errors.As(err, &pathErr) {
+			return pathErr.Path
+		}
+		return ""
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		pi, pj := path(errs[i]), path(errs[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return errs[i].Error() < errs[j].Error()
+	})
+}
+
+// uniqueErrors drops repeated errors, comparing typed errors structurally
+// and other errors by message.
+func uniqueErrors(slice []error) []error {
+	keys := map[any]bool{}
+	list := []error{}
+	for _, entry := range slice {
+		key := errorKey(entry)
+		if !keys[key] {
+			keys[key] = true
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+func errorKey(err error) any {
+	if e, ok := err.(*PathError); ok {
+		switch e.Err.(type) {
+		case LoopError, DuplicateError, EmptyStructError:
+			return *e
+		}
+	}
+	return err.Error()
+}
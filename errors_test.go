@@ -0,0 +1,53 @@
+package model_reflect_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type dupLeft struct{ ID int }
+
+type dupRight struct{ ID string }
+
+type typedErrorStruct struct {
+	Empty struct{}
+	Dup   struct {
+		dupLeft
+		dupRight
+	}
+	Again []typedErrorStruct
+}
+
+func TestTypedErrors(t *testing.T) {
+	m, _ := model_reflect.New(typedErrorStruct{})
+	var (
+		loop  model_reflect.LoopError
+		dup   model_reflect.DuplicateError
+		empty model_reflect.EmptyStructError
+	)
+	counts := map[string]int{}
+	for _, err := range m.Errs {
+		switch {
+		case errors.As(err, &loop):
+			counts["loop"]++
+		case errors.As(err, &dup):
+			counts["dup"]++
+		case errors.As(err, &empty):
+			counts["empty"]++
+		}
+	}
+	if !reflect.DeepEqual(counts, map[string]int{"loop": 1, "dup": 1, "empty": 2}) {
+		t.Fatalf("typed errors: %v %v", counts, m.Errs)
+	}
+	if loop.Type != reflect.TypeOf(typedErrorStruct{}) || dup.Name != "ID" || dup.Count != 2 ||
+		empty.Type != reflect.TypeOf(struct{}{}) {
+		t.Errorf("typed errors: %+v %+v %+v", loop, dup, empty)
+	}
+	if !errors.Is(loop, model_reflect.ErrLoopDetected) || !errors.Is(dup, model_reflect.ErrDuplicate) ||
+		!errors.Is(empty, model_reflect.ErrEmptyStruct) || errors.Is(loop, model_reflect.ErrDuplicate) {
+		t.Error("typed errors: sentinels")
+	}
+}
@@ -0,0 +1,23 @@
+package model_reflect
+
+import "reflect"
+
+// TuneStep exposes the parameter steps of TuneHasher.
+func TuneStep(h HashInfo) HashInfo {
+	return h.tuneStep()
+}
+
+// UnregisterWellKnownType removes t from the well-known types, so that tests
+// registering types do not leak them into later tests.
+func UnregisterWellKnownType(t reflect.Type) {
+	wellKnown.Lock()
+	defer wellKnown.Unlock()
+	m := make(map[reflect.Type]string, len(wellKnown.m))
+	for k, v := range wellKnown.m {
+		if k != t {
+			m[k] = v
+		}
+	}
+	wellKnown.m = m
+	ClearCache()
+}
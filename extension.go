@@ -0,0 +1,47 @@
+package model_reflect
+
+import (
+	"reflect"
+	"strings"
+)
+
+type (
+	// Extension lets a package outside this one claim types and describe
+	// them, so that adapters for ORMs, codecs and similar libraries can ship
+	// their own rules. Extensions are registered with WithExtensions.
+	Extension interface {
+		// Name identifies the extension and its rules in the model cache;
+		// it must change whenever Describe changes its results.
+		Name() string
+		// Describe returns the fragment of t, pointers removed, and true
+		// if the extension claims t.
+		Describe(t reflect.Type) (Fragment, bool)
+	}
+
+	// Fragment is the canonical form of a type claimed by an Extension.
+	// It is rendered as Repr, followed by the models of Children in angle
+	// brackets when there are any, as in nullable<int64>.
+	Fragment struct {
+		Repr     string
+		Children []reflect.Type
+	}
+)
+
+// describe returns the fragment of t from the first extension claiming it.
+func (c *config) describe(t reflect.Type) (Fragment, bool) {
+	for _, ext := range c.extensions {
+		if f, ok := ext.Describe(t); ok {
+			return f, true
+		}
+	}
+	return Fragment{}, false
+}
+
+// extensionsKey returns a string identifying the registered extensions.
+func extensionsKey(exts []Extension) string {
+	names := make([]string, len(exts))
+	for i, ext := range exts {
+		names[i] = ext.Name()
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,62 @@
+package model_reflect_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type optional[T any] struct {
+	value T
+	valid bool
+}
+
+type extensionStruct struct {
+	Count optional[int64]
+	Names optional[[]string]
+	Plain int
+}
+
+// optionalExtension renders optional[T] as optional<T>.
+type optionalExtension struct {
+	name string
+}
+
+func (e optionalExtension) Name() string {
+	return e.name
+}
+
+func (optionalExtension) Describe(t reflect.Type) (model_reflect.Fragment, bool) {
+	if !strings.HasPrefix(t.Name(), "optional[") {
+		return model_reflect.Fragment{}, false
+	}
+	return model_reflect.Fragment{Repr: "optional", Children: []reflect.Type{t.Field(0).Type}}, true
+}
+
+func TestWithExtensions(t *testing.T) {
+	plain, _ := model_reflect.New(extensionStruct{})
+	if plain.String() != "{ Count:{  }, Names:{  }, Plain:int }" {
+		t.Errorf("default: %s", plain)
+	}
+	ext := model_reflect.WithExtensions(optionalExtension{name: "optional"})
+	model, err := model_reflect.New(extensionStruct{}, ext)
+	want := "{ Count:optional<int64>, Names:optional<[]string>, Plain:int }"
+	if err != nil || model.String() != want {
+		t.Errorf("extension: %s [%v]", model, err)
+	}
+	if f := model.Model().Fields[0]; f.Kind != model_reflect.KindCustom || len(f.Args) != 1 {
+		t.Errorf("node: %v %d", f.Kind, len(f.Args))
+	}
+	parsed, err := model_reflect.Parse(want)
+	if err != nil || parsed.String() != want || parsed.Fields[1].Args[0].Kind != model_reflect.KindSlice {
+		t.Errorf("parse: %v [%v]", parsed, err)
+	}
+
+	renamed := optionalExtension{name: "optional/v2"}
+	model, _ = model_reflect.New(extensionStruct{}, model_reflect.WithExtensions(renamed, optionalExtension{}))
+	if model.String() != want {
+		t.Errorf("first extension: %s", model)
+	}
+}
@@ -0,0 +1,60 @@
+package model_reflect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Versions of the canonical syntax. A stored string records the version it
+// was written in, so that syntax changes can be introduced as new versions
+// without invalidating hashes of the old ones.
+const (
+	// FormatV1 is the original, unversioned syntax. It remains the default
+	// so that existing hashes stay valid.
+	FormatV1 = 1
+	// FormatV2 is the FormatV1 syntax preceded by its version, as in
+	// "v2 { A:int }".
+	FormatV2 = 2
+	// FormatLatest is the newest supported version.
+	FormatLatest = FormatV2
+)
+
+// WithFormat selects the version of the canonical syntax. It panics for
+// versions other than FormatV1 through FormatLatest.
+func WithFormat(version int) Option {
+	if version < FormatV1 || version > FormatLatest {
+		panic(fmt.Sprintf("model_reflect: unsupported format version %d", version))
+	}
+	return func(c *config) {
+		c.format = version
+	}
+}
+
+// FormatVersion returns the version of the canonical syntax of the model.
+func (m ModelInfo) FormatVersion() int {
+	version, _ := splitFormat(m.string)
+	return version
+}
+
+// formatPrefix returns the version prefix written in front of the model.
+func formatPrefix(version int) string {
+	if version <= FormatV1 {
+		return ""
+	}
+	return "v" + strconv.Itoa(version) + " "
+}
+
+// splitFormat returns the version of s and the model following its prefix.
+// Unprefixed strings are FormatV1.
+func splitFormat(s string) (int, string) {
+	if !strings.HasPrefix(s, "v") {
+		return FormatV1, s
+	}
+	prefix, rest, ok := strings.Cut(s[1:], " ")
+	version, err := strconv.Atoi(prefix)
+	if !ok || err != nil || version <= FormatV1 {
+		return FormatV1, s
+	}
+	return version, rest
+}
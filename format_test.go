@@ -0,0 +1,39 @@
+package model_reflect_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestWithFormat(t *testing.T) {
+	v1, _ := model_reflect.New(point{})
+	legacy, _ := model_reflect.New(point{}, model_reflect.WithFormat(model_reflect.FormatV1))
+	v2, _ := model_reflect.New(point{}, model_reflect.WithFormat(model_reflect.FormatV2))
+	if v1.FormatVersion() != model_reflect.FormatV1 || legacy.Hash() != v1.Hash() {
+		t.Errorf("v1: %s %d", v1, v1.FormatVersion())
+	}
+	if v2.String() != "v2 { X:int, Y:int }" || v2.FormatVersion() != model_reflect.FormatV2 || v2.Hash() == v1.Hash() {
+		t.Errorf("v2: %s %d", v2, v2.FormatVersion())
+	}
+	var b strings.Builder
+	if _, err := v2.WriteTo(&b); err != nil || b.String() != v2.String() {
+		t.Errorf("write to: %s [%v]", b.String(), err)
+	}
+	decoded := model_reflect.ModelInfo{}
+	if err := decoded.UnmarshalText([]byte(v2.String())); err != nil || decoded.Hash() != v2.Hash() ||
+		decoded.FormatVersion() != model_reflect.FormatV2 {
+		t.Errorf("unmarshal: %s [%v]", decoded, err)
+	}
+	if _, err := model_reflect.Parse("v9 { X:int }"); !errors.Is(err, model_reflect.ErrSyntax) {
+		t.Errorf("future version: %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic for unsupported version")
+		}
+	}()
+	model_reflect.WithFormat(0)
+}
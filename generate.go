@@ -0,0 +1,49 @@
+package model_reflect
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+)
+
+// GenerateHashes writes a Go source file of package pkg declaring the
+// constant <name>ModelHash for every entry of values, so the expected hash
+// is compiled in and a test comparing it to New(v).Hash() fails on drift.
+// Values whose reflection reports errors fail the generation. It is meant to
+// be called from a small main run by go:generate:
+//
+//	//go:generate go run ./internal/genhashes
+//
+//	func main() {
+//		f, _ := os.Create("model_hashes.go")
+//		defer f.Close()
+//		err := model_reflect.GenerateHashes(f, "orders", map[string]any{"User": User{}})
+//		...
+//	}
+func GenerateHashes(w io.Writer, pkg string, values map[string]any, opts ...Option) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "// Code generated by model_reflect.GenerateHashes; DO NOT EDIT.\n\npackage %s\n\nconst (\n", pkg)
+	for _, name := range names {
+		m, err := New(values[name], opts...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		fmt.Fprintf(b, "\t// %sModelHash is the hash of %s.\n", name, m)
+		fmt.Fprintf(b, "\t%sModelHash uint64 = %#016x\n", name, m.Hash())
+	}
+	b.WriteString(")\n")
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
@@ -0,0 +1,31 @@
+package model_reflect_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestGenerateHashes(t *testing.T) {
+	b := &bytes.Buffer{}
+	err := model_reflect.GenerateHashes(b, "models", map[string]any{"Segment": segment{}, "Point": point{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := model_reflect.New(point{})
+	s, _ := model_reflect.New(segment{})
+	src := b.String()
+	if !strings.HasPrefix(src, "// Code generated by model_reflect.GenerateHashes; DO NOT EDIT.\n\npackage models\n") ||
+		!strings.Contains(src, fmt.Sprintf("PointModelHash uint64 = %#016x", p.Hash())) ||
+		!strings.Contains(src, fmt.Sprintf("SegmentModelHash uint64 = %#016x", s.Hash())) ||
+		strings.Index(src, "Point") > strings.Index(src, "Segment") {
+		t.Errorf("generated:\n%s", src)
+	}
+	if err := model_reflect.GenerateHashes(b, "models", map[string]any{"Loop": (*testA)(nil)}); !errors.Is(err, model_reflect.ErrLoopDetected) {
+		t.Errorf("loop: %v", err)
+	}
+}
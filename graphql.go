@@ -0,0 +1,134 @@
+package model_reflect
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var graphqlScalars = map[string]string{
+	"bool":    "Boolean",
+	"string":  "String",
+	"int8":    "Int",
+	"int16":   "Int",
+	"int32":   "Int",
+	"uint8":   "Int",
+	"uint16":  "Int",
+	"int":     "Int64",
+	"int64":   "Int64",
+	"uint":    "Int64",
+	"uint32":  "Int64",
+	"uint64":  "Int64",
+	"float32": "Float",
+	"float64": "Float",
+}
+
+var graphqlBuiltins = map[string]bool{
+	"Boolean": true, "String": true, "Int": true, "Float": true, "ID": true,
+}
+
+// GraphQL returns GraphQL SDL type definitions for the model. Pointers
+// become nullable fields, slices and arrays lists, and values without a
+// GraphQL counterpart (64-bit integers, maps, opaque types) custom scalars.
+func (m ModelInfo) GraphQL() (string, error) {
+	if m.root == nil || m.root.Kind == KindNil {
+		return "", ErrNoType
+	}
+	if m.root.Kind != KindStruct || m.root.namedType() == "" {
+		return "", fmt.Errorf("%w %s", ErrUnnamed, m)
+	}
+	g := &graphqlGen{done: map[any]bool{}, scalars: map[string]bool{}}
+	types := []string{}
+	g.queue = append(g.queue, namedModel{messageName(m.root.namedType()), m.root})
+	for len(g.queue) > 0 {
+		n := g.queue[0]
+		g.queue = g.queue[1:]
+		if n.namedType() != "" {
+			if g.done[n.typeKey()] {
+				continue
+			}
+			g.done[n.typeKey()] = true
+		}
+		types = append(types, g.object(n.typeName, n.Model))
+	}
+	scalars := []string{}
+	for s := range g.scalars {
+		scalars = append(scalars, "scalar "+s+"\n")
+	}
+	sort.Strings(scalars)
+	b := strings.Join(scalars, "")
+	if b != "" {
+		b += "\n"
+	}
+	return b + strings.Join(types, "\n"), nil
+}
+
+type namedModel struct {
+	typeName string
+	*Model
+}
+
+type graphqlGen struct {
+	done    map[any]bool
+	scalars map[string]bool
+	queue   []namedModel
+}
+
+func (g *graphqlGen) object(name string, n *Model) string {
+	b := &strings.Builder{}
+	b.WriteString("type " + name + " {\n")
+	for _, f := range n.Fields {
+		fmt.Fprintf(b, "  %s: %s\n", f.wire(), g.field(f, name+messageName(f.Name)))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (g *graphqlGen) field(n *Model, name string) string {
+	typ := g.typ(n, name)
+	if n.Nullable {
+		return typ
+	}
+	return typ + "!"
+}
+
+func (g *graphqlGen) typ(n *Model, name string) string {
+	switch n.Kind {
+	case KindScalar, KindLiteral:
+		if typ, ok := graphqlScalars[n.Repr]; ok {
+			return g.scalar(typ)
+		}
+	case KindOpaque:
+		if strings.Contains(n.Repr, "TextMarshaler") {
+			return "String"
+		}
+	case KindSlice, KindArray:
+		if isBytes(n) {
+			return "String"
+		}
+		return "[" + g.field(n.Elem, name) + "]"
+	case KindNullable:
+		return g.typ(n.Elem, name)
+	case KindMap:
+		return g.scalar("Map")
+	case KindLoop, KindRef:
+		if typeName := n.namedType(); typeName != "" {
+			return messageName(typeName)
+		}
+	case KindStruct:
+		if typeName := n.namedType(); typeName != "" {
+			name = messageName(typeName)
+		}
+		g.queue = append(g.queue, namedModel{name, n})
+		return name
+	}
+	return g.scalar("Any")
+}
+
+// scalar records a custom scalar so its declaration is emitted.
+func (g *graphqlGen) scalar(name string) string {
+	if !graphqlBuiltins[name] {
+		g.scalars[name] = true
+	}
+	return name
+}
@@ -0,0 +1,53 @@
+package model_reflect_test
+
+import (
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type gqlAuthor struct {
+	Name string `json:"name"`
+}
+
+type gqlPost struct {
+	ID       int64             `json:"id"`
+	Title    string            `json:"title"`
+	Author   *gqlAuthor        `json:"author"`
+	Tags     []string          `json:"tags"`
+	Meta     map[string]string `json:"meta"`
+	Revision struct {
+		Number int32 `json:"number"`
+	} `json:"revision"`
+}
+
+func TestGraphQL(t *testing.T) {
+	model, _ := model_reflect.New(gqlPost{})
+	s, err := model.GraphQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `scalar Int64
+scalar Map
+
+type GqlPost {
+  author: GqlAuthor
+  id: Int64!
+  meta: Map!
+  revision: GqlPostRevision!
+  tags: [String!]!
+  title: String!
+}
+
+type GqlAuthor {
+  name: String!
+}
+
+type GqlPostRevision {
+  number: Int!
+}
+`
+	if s != want {
+		t.Errorf("graphql:\n%s\nwant:\n%s", s, want)
+	}
+}
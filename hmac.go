@@ -0,0 +1,34 @@
+package model_reflect
+
+import (
+	"crypto/hmac"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// HMACHasher MACs the model with a shared Key, so that fingerprints cannot
+// be forged without it. Hash defaults to SHA-256; digests longer than its
+// output are extended with HKDF-Expand. Models using it cannot be
+// marshaled, which would expose the key.
+type HMACHasher struct {
+	Key  []byte
+	Hash func() hash.Hash
+}
+
+// Sum returns the MAC of the model.
+func (h HMACHasher) Sum(model []byte, size int) []byte {
+	fn := hashOrSHA256(h.Hash)
+	mac := hmac.New(fn, h.Key)
+	mac.Write(model)
+	sum := mac.Sum(nil)
+	if size <= len(sum) {
+		return sum[:size]
+	}
+	key := make([]byte, size)
+	if _, err := io.ReadFull(hkdf.Expand(fn, sum, nil), key); err != nil {
+		panic("model_reflect: hmac: " + err.Error())
+	}
+	return key
+}
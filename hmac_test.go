@@ -0,0 +1,33 @@
+package model_reflect_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestHMACHasher(t *testing.T) {
+	key := []byte("shared secret")
+	model, _ := model_reflect.New(point{}, model_reflect.WithHasher(model_reflect.HMACHasher{Key: key}))
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(model.String()))
+	sum := mac.Sum(nil)
+	if h := model.Hash256(); !hmac.Equal(h[:], sum) || model.Hash() != binary.LittleEndian.Uint64(sum) {
+		t.Errorf("mac: %x", h)
+	}
+	other, _ := model_reflect.New(point{}, model_reflect.WithHasher(model_reflect.HMACHasher{Key: []byte("other")}))
+	if other.Hash() == model.Hash() {
+		t.Error("key ignored")
+	}
+	if long := (model_reflect.HMACHasher{Key: key}).Sum([]byte("x"), 100); len(long) != 100 {
+		t.Errorf("long digest: %d", len(long))
+	}
+	if _, err := json.Marshal(model); !errors.Is(err, model_reflect.ErrUnknownHasher) {
+		t.Errorf("marshaled key: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package model_reflect
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+var implementations = struct {
+	sync.RWMutex
+	m map[reflect.Type][]reflect.Type
+}{}
+
+// RegisterImplementations registers the concrete types that may be stored in
+// fields of interface type iface. Such fields are rendered as the union of
+// their implementations instead of being skipped. Implementations are given
+// as values, e.g. Circle{} or (*Square)(nil). It panics if iface is not an
+// interface type or an implementation does not implement it.
+func RegisterImplementations(iface reflect.Type, impls ...any) {
+	types := implementationTypes(iface, impls)
+	implementations.Lock()
+	defer implementations.Unlock()
+	implementations.m = addImplementations(implementations.m, iface, types)
+	ClearCache()
+}
+
+// WithImplementations is like RegisterImplementations for a single call.
+func WithImplementations(iface reflect.Type, impls ...any) Option {
+	types := implementationTypes(iface, impls)
+	return func(c *config) {
+		c.impls = addImplementations(c.impls, iface, types)
+	}
+}
+
+func implementationTypes(iface reflect.Type, impls []any) []reflect.Type {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("model_reflect: %v is not an interface type", iface))
+	}
+	types := make([]reflect.Type, 0, len(impls))
+	for _, impl := range impls {
+		t := reflect.TypeOf(impl)
+		if t == nil || !t.Implements(iface) {
+			panic(fmt.Sprintf("model_reflect: %v does not implement %v", t, iface))
+		}
+		types = append(types, t)
+	}
+	return types
+}
+
+// addImplementations returns a copy of m with types added to iface.
+func addImplementations(m map[reflect.Type][]reflect.Type, iface reflect.Type, types []reflect.Type) map[reflect.Type][]reflect.Type {
+	result := cloneImplementations(m)
+	for _, t := range types {
+		if !slices.Contains(result[iface], t) {
+			result[iface] = append(result[iface], t)
+		}
+	}
+	return result
+}
+
+func cloneImplementations(m map[reflect.Type][]reflect.Type) map[reflect.Type][]reflect.Type {
+	result := make(map[reflect.Type][]reflect.Type, len(m))
+	for iface, types := range m {
+		result[iface] = slices.Clone(types)
+	}
+	return result
+}
+
+func registeredImplementations() map[reflect.Type][]reflect.Type {
+	implementations.RLock()
+	defer implementations.RUnlock()
+	return cloneImplementations(implementations.m)
+}
+
+// implementationsKey returns a deterministic description of m for the cache.
+func implementationsKey(m map[reflect.Type][]reflect.Type) string {
+	keys := []string{}
+	for iface, types := range m {
+		names := []string{}
+		for _, t := range types {
+			names = append(names, t.PkgPath()+"."+t.String())
+		}
+		sort.Strings(names)
+		keys = append(keys, iface.PkgPath()+"."+iface.String()+"="+strings.Join(names, ","))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ";")
+}
@@ -0,0 +1,62 @@
+package model_reflect_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type shape interface{ Area() float64 }
+
+type circle struct{ Radius float64 }
+
+func (c circle) Area() float64 { return c.Radius * c.Radius * 3 }
+
+type square struct{ Side int }
+
+func (s *square) Area() float64 { return float64(s.Side * s.Side) }
+
+type drawing struct {
+	Name   string
+	Shapes []shape
+	Main   shape
+}
+
+var shapeType = reflect.TypeOf((*shape)(nil)).Elem()
+
+func TestWithImplementations(t *testing.T) {
+	model, err := model_reflect.New(drawing{})
+	if err != nil || model.String() != "{ Name:string, Shapes:[]<?> }" {
+		t.Errorf("unregistered: %s [%v]", model, err)
+	}
+	model, err = model_reflect.New(drawing{},
+		model_reflect.WithImplementations(shapeType, (*square)(nil), circle{}))
+	want := "{ Main:({ Radius:float64 }|{ Side:int }), Name:string, Shapes:[]({ Radius:float64 }|{ Side:int }) }"
+	if err != nil || model.String() != want {
+		t.Errorf("registered: %s [%v]", model, err)
+	}
+}
+
+type (
+	event      interface{ event() }
+	clickEvent struct{ X, Y int }
+	eventLog   struct{ Events []event }
+)
+
+func (clickEvent) event() {}
+
+func TestRegisterImplementations(t *testing.T) {
+	before, _ := model_reflect.New(eventLog{})
+	model_reflect.RegisterImplementations(reflect.TypeOf((*event)(nil)).Elem(), clickEvent{})
+	after, _ := model_reflect.New(eventLog{})
+	if before.String() == after.String() || after.String() != "{ Events:[]({ X:int, Y:int }) }" {
+		t.Errorf("before %s, after %s", before, after)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a non-implementation did not panic")
+		}
+	}()
+	model_reflect.RegisterImplementations(shapeType, clickEvent{})
+}
@@ -0,0 +1,84 @@
+package model_reflect
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+
+	"golang.org/x/exp/slices"
+)
+
+var jsonInterfaces = []reflect.Type{
+	reflect.TypeOf((*json.Marshaler)(nil)).Elem(),
+	reflect.TypeOf((*json.Unmarshaler)(nil)).Elem(),
+}
+
+var gobInterfaces = []reflect.Type{
+	reflect.TypeOf((*gob.GobEncoder)(nil)).Elem(),
+	reflect.TypeOf((*gob.GobDecoder)(nil)).Elem(),
+}
+
+var sqlInterfaces = []reflect.Type{
+	reflect.TypeOf((*sql.Scanner)(nil)).Elem(),
+	reflect.TypeOf((*driver.Valuer)(nil)).Elem(),
+}
+
+// WithAddedInterfaces adds interfaces to the set that makes a type opaque,
+// keeping the ones configured so far.
+func WithAddedInterfaces(ifaces ...reflect.Type) Option {
+	return func(c *config) {
+		for _, iface := range ifaces {
+			if !slices.Contains(c.interfaces, iface) {
+				c.interfaces = append(c.interfaces, iface)
+			}
+		}
+	}
+}
+
+// WithJSONMarshalers makes types with custom JSON marshaling opaque, like
+// binary and text marshalers are by default.
+func WithJSONMarshalers() Option {
+	return WithAddedInterfaces(jsonInterfaces...)
+}
+
+// WithGobEncoders makes types with custom gob encoding opaque.
+func WithGobEncoders() Option {
+	return WithAddedInterfaces(gobInterfaces...)
+}
+
+// WithSQLValuers makes types implementing sql.Scanner or driver.Valuer,
+// such as sql.NullString, opaque, so that database models are represented
+// by their database-facing form rather than their struct layout.
+func WithSQLValuers() Option {
+	return WithAddedInterfaces(sqlInterfaces...)
+}
+
+// WithMessageInterface renders types implementing iface by their Go type
+// name, as in message(pb.Order), instead of their generated struct layout.
+// Pass proto.Message to treat protobuf messages this way without this
+// package depending on protobuf. It panics if iface is not an interface.
+func WithMessageInterface(iface reflect.Type) Option {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		panic("model_reflect: WithMessageInterface of non-interface type")
+	}
+	return func(c *config) {
+		if !slices.Contains(c.messages, iface) {
+			c.messages = append(slices.Clip(c.messages), iface)
+		}
+	}
+}
+
+// isMessage reports whether t implements a message interface.
+func (c *config) isMessage(t reflect.Type) bool {
+	if t.Kind() == reflect.Interface {
+		return false
+	}
+	for _, iface := range c.messages {
+		if reflect.PointerTo(t).Implements(iface) {
+			return true
+		}
+	}
+	return false
+}
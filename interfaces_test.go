@@ -0,0 +1,98 @@
+package model_reflect_test
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type (
+	jsonStatus  struct{ Code int }
+	jsonPayload struct {
+		Status jsonStatus
+		Count  int
+	}
+)
+
+func (jsonStatus) MarshalJSON() ([]byte, error) { return nil, nil }
+
+func (*jsonStatus) UnmarshalJSON([]byte) error { return nil }
+
+func TestWithJSONMarshalers(t *testing.T) {
+	if model, _ := model_reflect.New(jsonPayload{}); model.String() != "{ Count:int, Status:{ Code:int } }" {
+		t.Errorf("default: %s", model)
+	}
+	model, _ := model_reflect.New(jsonPayload{}, model_reflect.WithJSONMarshalers())
+	if model.String() != "{ Count:int, Status:<json.Marshaler,json.Unmarshaler> }" {
+		t.Errorf("json: %s", model)
+	}
+	again, _ := model_reflect.New(jsonPayload{}, model_reflect.WithJSONMarshalers(), model_reflect.WithJSONMarshalers())
+	if again.String() != model.String() {
+		t.Errorf("added twice: %s", again)
+	}
+}
+
+type gobVersion struct{ Major, Minor int }
+
+func (gobVersion) GobEncode() ([]byte, error) { return nil, nil }
+
+func (*gobVersion) GobDecode([]byte) error { return nil }
+
+func TestWithGobEncoders(t *testing.T) {
+	type release struct{ Version gobVersion }
+	if model, _ := model_reflect.New(release{}); model.String() != "{ Version:{ Major:int, Minor:int } }" {
+		t.Errorf("default: %s", model)
+	}
+	model, _ := model_reflect.New(release{}, model_reflect.WithGobEncoders())
+	if model.String() != "{ Version:<gob.GobDecoder,gob.GobEncoder> }" {
+		t.Errorf("gob: %s", model)
+	}
+}
+
+func TestWithSQLValuers(t *testing.T) {
+	type row struct {
+		Name sql.NullString
+		ID   int64
+	}
+	if model, _ := model_reflect.New(row{}); model.String() != "{ ID:int64, Name:{ String:string, Valid:bool } }" {
+		t.Errorf("default: %s", model)
+	}
+	model, _ := model_reflect.New(row{}, model_reflect.WithSQLValuers())
+	if model.String() != "{ ID:int64, Name:<driver.Valuer,sql.Scanner> }" {
+		t.Errorf("sql: %s", model)
+	}
+}
+
+type (
+	protoMessage interface{ ProtoReflect() }
+	orderProto   struct {
+		ID               int
+		XXX_unrecognized []byte //nolint:revive,stylecheck
+	}
+	orderEnvelope struct {
+		Order  *orderProto
+		Orders []orderProto
+	}
+)
+
+func (*orderProto) ProtoReflect() {}
+
+func TestWithMessageInterface(t *testing.T) {
+	iface := reflect.TypeOf((*protoMessage)(nil)).Elem()
+	model, err := model_reflect.New(orderEnvelope{}, model_reflect.WithMessageInterface(iface))
+	want := "{ Order:message(model_reflect_test.orderProto), Orders:[]message(model_reflect_test.orderProto) }"
+	if err != nil || model.String() != want {
+		t.Errorf("messages: %s [%v]", model, err)
+	}
+	if tree, err := model_reflect.Parse(want); err != nil || tree.String() != want {
+		t.Errorf("parse: %s [%v]", tree, err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic for non-interface")
+		}
+	}()
+	model_reflect.WithMessageInterface(reflect.TypeOf(orderProto{}))
+}
@@ -0,0 +1,259 @@
+package model_reflect
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// JSONSchemaDraft is the dialect of the documents emitted by JSONSchema.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// ErrNoType is returned by exporters for models without a root type.
+var ErrNoType = errors.New("model has no type")
+
+// JSONSchema returns a draft 2020-12 JSON Schema document for the model.
+// Named struct types appearing more than once, including recursive ones,
+// are emitted once under $defs and referenced with $ref.
+func (m ModelInfo) JSONSchema() ([]byte, error) {
+	if m.root == nil || m.root.Kind == KindNil {
+		return nil, ErrNoType
+	}
+	s := newSchemaBuilder("#/$defs/", false)
+	s.count(m.root)
+	doc := s.schema(m.root)
+	doc["$schema"] = JSONSchemaDraft
+	if name := m.root.namedType(); name != "" {
+		doc["title"] = name
+	}
+	if len(s.defs) > 0 {
+		doc["$defs"] = s.defs
+	}
+	return marshalIndent(doc)
+}
+
+// marshalIndent encodes v as indented JSON without HTML escaping, keeping
+// canonical strings such as <...> readable.
+func marshalIndent(v any) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// schemaBuilder converts model trees into JSON Schema objects. It is shared
+// by the JSON Schema and OpenAPI exporters.
+type schemaBuilder struct {
+	prefix string
+	refAll bool
+	names  map[any]string
+	taken  map[string]bool
+	counts map[any]int
+	defs   map[string]any
+}
+
+func newSchemaBuilder(prefix string, refAll bool) *schemaBuilder {
+	return &schemaBuilder{
+		prefix: prefix,
+		refAll: refAll,
+		names:  map[any]string{},
+		taken:  map[string]bool{},
+		counts: map[any]int{},
+		defs:   map[string]any{},
+	}
+}
+
+// count records how often each named struct type occurs in the tree.
+func (s *schemaBuilder) count(n *Model) {
+	if n == nil {
+		return
+	}
+	if (n.Kind == KindStruct || n.Kind == KindLoop || n.Kind == KindRef) && n.namedType() != "" {
+		s.counts[n.typeKey()]++
+	}
+	s.count(n.Key)
+	s.count(n.Elem)
+	for _, f := range n.Fields {
+		s.count(f)
+	}
+	for _, v := range n.Variants {
+		s.count(v)
+	}
+}
+
+func (s *schemaBuilder) shared(n *Model) bool {
+	return n.namedType() != "" && (s.refAll || s.counts[n.typeKey()] > 1)
+}
+
+// name returns the definition name of the type of n, reserving a new one if
+// needed.
+func (s *schemaBuilder) name(n *Model) (string, bool) {
+	key := n.typeKey()
+	if name, ok := s.names[key]; ok {
+		return name, false
+	}
+	name := n.namedType()
+	for i := 2; s.taken[name]; i++ {
+		name = n.namedType() + "_" + strconv.Itoa(i)
+	}
+	s.taken[name] = true
+	s.names[key] = name
+	return name, true
+}
+
+func (s *schemaBuilder) ref(n *Model) map[string]any {
+	if n.namedType() == "" {
+		return map[string]any{}
+	}
+	name, created := s.name(n)
+	if created && n.Kind == KindStruct {
+		s.defs[name] = s.object(n)
+	}
+	return map[string]any{"$ref": s.prefix + name}
+}
+
+func (s *schemaBuilder) schema(n *Model) map[string]any {
+	result := s.typeSchema(n)
+	if n != nil && n.Nullable {
+		if t, ok := result["type"].(string); ok {
+			result["type"] = []string{t, "null"}
+		}
+	}
+	return result
+}
+
+func (s *schemaBuilder) typeSchema(n *Model) map[string]any {
+	if n == nil {
+		return map[string]any{}
+	}
+	switch n.Kind {
+	case KindLoop, KindRef:
+		return s.ref(n)
+	case KindStruct:
+		if s.shared(n) {
+			return s.ref(n)
+		}
+		return s.object(n)
+	case KindSlice:
+		if n.Elem.Kind == KindScalar && n.Elem.Repr == "uint8" {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": s.schema(n.Elem)}
+	case KindArray:
+		return map[string]any{
+			"type":     "array",
+			"items":    s.schema(n.Elem),
+			"minItems": n.Len,
+			"maxItems": n.Len,
+		}
+	case KindMap:
+		return map[string]any{"type": "object", "additionalProperties": s.schema(n.Elem)}
+	case KindScalar:
+		if t := jsonType(n.Repr); t != "" {
+			return map[string]any{"type": t}
+		}
+	case KindOpaque:
+		if strings.Contains(n.Repr, "TextMarshaler") {
+			return map[string]any{"type": "string"}
+		}
+	case KindLiteral:
+		return map[string]any{"$comment": n.Repr}
+	case KindNullable:
+		return s.typeSchema(n.Elem)
+	case KindNamed:
+		if schema, ok := namedSchemas[n.Repr]; ok {
+			result := map[string]any{}
+			for k, v := range schema {
+				result[k] = v
+			}
+			return result
+		}
+	case KindUnion:
+		variants := []any{}
+		for _, v := range n.Variants {
+			variants = append(variants, s.schema(v))
+		}
+		return map[string]any{"oneOf": variants}
+	}
+	return map[string]any{}
+}
+
+func (s *schemaBuilder) object(n *Model) map[string]any {
+	properties := map[string]any{}
+	for _, f := range n.Fields {
+		properties[f.wire()] = s.schema(f)
+	}
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+// wire returns the encoded name of a field node.
+func (n *Model) wire() string {
+	if n.WireName != "" {
+		return n.WireName
+	}
+	return n.Name
+}
+
+// namedType returns the name of the Go type of n. Trees without Go types,
+// such as parsed ones, only record the names of types rendered with
+// WithTypeNames, WithTypeRefs or WithCycleRefs; it is empty for all others.
+func (n *Model) namedType() string {
+	switch {
+	case n.Type != nil:
+		return n.Type.Name()
+	case n.TypeName != "":
+		name := n.TypeName
+		end := strings.IndexByte(name, '[')
+		if end < 0 {
+			end = len(name)
+		}
+		return name[strings.LastIndexByte(name[:end], '.')+1:]
+	case (n.Kind == KindLoop || n.Kind == KindRef) && strings.HasPrefix(n.Repr, "@"):
+		return n.Repr[1:]
+	}
+	return ""
+}
+
+// typeKey identifies the type of n for exporters: its Go type, or its name
+// in trees without Go types.
+func (n *Model) typeKey() any {
+	if n.Type != nil {
+		return n.Type
+	}
+	return n.namedType()
+}
+
+// namedSchemas maps the names of well-known types to their JSON Schema.
+var namedSchemas = map[string]map[string]any{
+	"time":     {"type": "string", "format": "date-time"},
+	"duration": {"type": "integer"},
+	"url":      {"type": "string", "format": "uri"},
+	"uuid":     {"type": "string", "format": "uuid"},
+	"bigint":   {"type": "integer"},
+	"bigfloat": {"type": "string"},
+	"bigrat":   {"type": "string"},
+	"ipaddr":   {"type": "string"},
+	"ipprefix": {"type": "string"},
+}
+
+// jsonType maps a scalar kind to its JSON Schema type.
+func jsonType(kind string) string {
+	switch kind {
+	case "bool":
+		return "boolean"
+	case "string":
+		return "string"
+	case "float32", "float64":
+		return "number"
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr":
+		return "integer"
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package model_reflect_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type schemaNode struct {
+	Value    int           `json:"value"`
+	Data     []byte        `json:"data"`
+	Children []*schemaNode `json:"children"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	model, _ := model_reflect.New(schemaNode{})
+	b, err := model.JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%s", b)
+	var doc struct {
+		Schema string                    `json:"$schema"`
+		Ref    string                    `json:"$ref"`
+		Defs   map[string]map[string]any `json:"$defs"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Schema != model_reflect.JSONSchemaDraft || doc.Ref != "#/$defs/schemaNode" {
+		t.Errorf("root: %+v", doc)
+	}
+	props, _ := doc.Defs["schemaNode"]["properties"].(map[string]any)
+	if len(props) != 3 || props["data"] == nil {
+		t.Errorf("properties: %v", props)
+	}
+	if _, err := (model_reflect.ModelInfo{}).JSONSchema(); err == nil {
+		t.Error("expected error for empty model")
+	}
+}
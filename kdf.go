@@ -0,0 +1,108 @@
+package model_reflect
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+type (
+	// ScryptHasher derives digests with scrypt. N must be a power of two
+	// greater than one, R and P positive and R*P below 2^30; Sum panics
+	// otherwise.
+	ScryptHasher struct {
+		Salt []byte
+		N    int
+		R    int
+		P    int
+	}
+
+	// HKDFHasher derives digests with HKDF (RFC 5869). Hash defaults to
+	// SHA-256. Digests are limited to 255 times the hash size.
+	HKDFHasher struct {
+		Salt []byte
+		Info []byte
+		Hash func() hash.Hash
+	}
+
+	// PBKDF2Hasher derives digests with PBKDF2 (RFC 8018). Hash defaults to
+	// SHA-256, which with HKDFHasher makes the FIPS-approved choices.
+	PBKDF2Hasher struct {
+		Salt       []byte
+		Iterations int
+		Hash       func() hash.Hash
+	}
+)
+
+// Sum returns the scrypt key of the model.
+func (h ScryptHasher) Sum(model []byte, size int) []byte {
+	key, err := scrypt.Key(model, h.Salt, h.N, h.R, h.P, size)
+	if err != nil {
+		panic(fmt.Sprintf("model_reflect: scrypt: %v", err))
+	}
+	return key
+}
+
+// Namespace returns a copy of h whose salt is the SHA-256 of namespace.
+func (h ScryptHasher) Namespace(namespace string) Hasher {
+	h.Salt = namespaceSalt(namespace)
+	return h
+}
+
+// Validate reports whether Sum can derive a key with the parameters of h.
+func (h ScryptHasher) Validate() error {
+	switch {
+	case h.N <= 1 || h.N&(h.N-1) != 0:
+		return fmt.Errorf("%w: scrypt N %d", ErrInvalidHasher, h.N)
+	case h.R < 1 || h.P < 1 || uint64(h.R)*uint64(h.P) >= 1<<30:
+		return fmt.Errorf("%w: scrypt r %d p %d", ErrInvalidHasher, h.R, h.P)
+	}
+	return nil
+}
+
+// Sum returns the HKDF output for the model as input key material.
+func (h HKDFHasher) Sum(model []byte, size int) []byte {
+	key := make([]byte, size)
+	if _, err := io.ReadFull(hkdf.New(hashOrSHA256(h.Hash), model, h.Salt, h.Info), key); err != nil {
+		panic(fmt.Sprintf("model_reflect: hkdf: %v", err))
+	}
+	return key
+}
+
+// Namespace returns a copy of h whose salt is the SHA-256 of namespace.
+func (h HKDFHasher) Namespace(namespace string) Hasher {
+	h.Salt = namespaceSalt(namespace)
+	return h
+}
+
+// Sum returns the PBKDF2 key of the model.
+func (h PBKDF2Hasher) Sum(model []byte, size int) []byte {
+	return pbkdf2.Key(model, h.Salt, h.Iterations, size, hashOrSHA256(h.Hash))
+}
+
+// Validate reports whether Sum can derive a key with the parameters of h:
+// Iterations must be positive.
+func (h PBKDF2Hasher) Validate() error {
+	if h.Iterations < 1 {
+		return fmt.Errorf("%w: pbkdf2 iterations %d", ErrInvalidHasher, h.Iterations)
+	}
+	return nil
+}
+
+// Namespace returns a copy of h whose salt is the SHA-256 of namespace.
+func (h PBKDF2Hasher) Namespace(namespace string) Hasher {
+	h.Salt = namespaceSalt(namespace)
+	return h
+}
+
+func hashOrSHA256(h func() hash.Hash) func() hash.Hash {
+	if h == nil {
+		return sha256.New
+	}
+	return h
+}
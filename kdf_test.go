@@ -0,0 +1,75 @@
+package model_reflect_test
+
+import (
+	"crypto/sha512"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+var kdfHashers = []model_reflect.Hasher{
+	model_reflect.ScryptHasher{Salt: []byte("salt"), N: 1024, R: 8, P: 1},
+	model_reflect.HKDFHasher{Salt: []byte("salt"), Info: []byte("model")},
+	model_reflect.PBKDF2Hasher{Salt: []byte("salt"), Iterations: 1000},
+}
+
+func TestKDFHashers(t *testing.T) {
+	base, _ := model_reflect.New(point{})
+	seen := map[uint64]bool{base.Hash(): true}
+	for _, h := range kdfHashers {
+		model, _ := model_reflect.New(point{}, model_reflect.WithHasher(h))
+		again, _ := model_reflect.New(point{}, model_reflect.WithHasher(h))
+		if model.Hash() != again.Hash() || seen[model.Hash()] {
+			t.Errorf("%T: %x", h, model.Hash())
+		}
+		seen[model.Hash()] = true
+
+		data, err := json.Marshal(model)
+		decoded := model_reflect.ModelInfo{}
+		if err != nil || json.Unmarshal(data, &decoded) != nil || decoded.Hash() != model.Hash() {
+			t.Errorf("%T json: %s [%v]", h, data, err)
+		}
+		data, err = model.MarshalBinary()
+		decoded = model_reflect.ModelInfo{}
+		if err != nil || decoded.UnmarshalBinary(data) != nil || decoded.Hash() != model.Hash() {
+			t.Errorf("%T binary: %x [%v]", h, data, err)
+		}
+	}
+}
+
+func TestKDFCustomHash(t *testing.T) {
+	h := model_reflect.PBKDF2Hasher{Iterations: 10, Hash: sha512.New}
+	model, _ := model_reflect.New(point{}, model_reflect.WithHasher(h))
+	plain, _ := model_reflect.New(point{}, model_reflect.WithHasher(model_reflect.PBKDF2Hasher{Iterations: 10}))
+	if model.Hash() == plain.Hash() {
+		t.Error("hash function ignored")
+	}
+	if _, err := json.Marshal(model); !errors.Is(err, model_reflect.ErrUnknownHasher) {
+		t.Errorf("custom hash marshaled: %v", err)
+	}
+}
+
+func TestKDFValidate(t *testing.T) {
+	for _, h := range kdfHashers {
+		if v, ok := h.(interface{ Validate() error }); ok && v.Validate() != nil {
+			t.Errorf("%T: %v", h, v.Validate())
+		}
+	}
+	for _, h := range []interface{ Validate() error }{
+		model_reflect.ScryptHasher{N: 1, R: 8, P: 1},
+		model_reflect.ScryptHasher{N: 1000, R: 8, P: 1},
+		model_reflect.ScryptHasher{N: 1024, R: 0, P: 1},
+		model_reflect.ScryptHasher{N: 1024, R: 1 << 15, P: 1 << 15},
+		model_reflect.PBKDF2Hasher{},
+	} {
+		if err := h.Validate(); !errors.Is(err, model_reflect.ErrInvalidHasher) {
+			t.Errorf("%+v: %v", h, err)
+		}
+		model, _ := model_reflect.New(point{}, model_reflect.WithHasher(h.(model_reflect.Hasher)))
+		if _, err := model.SafeHash(); !errors.Is(err, model_reflect.ErrInvalidHasher) {
+			t.Errorf("%+v safe hash: %v", h, err)
+		}
+	}
+}
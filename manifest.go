@@ -0,0 +1,47 @@
+package model_reflect
+
+import (
+	"runtime"
+	"time"
+)
+
+// Manifest is a schema attestation of a model, suitable for committing
+// alongside a release.
+type Manifest struct {
+	Model         string        `json:"model"`
+	Hash          string        `json:"hash"`
+	Fingerprint   string        `json:"fingerprint"`
+	Hasher        *HasherParams `json:"hasher"`
+	FormatVersion int           `json:"formatVersion"`
+	Package       string        `json:"package,omitempty"`
+	Type          string        `json:"type,omitempty"`
+	GoVersion     string        `json:"goVersion"`
+	Time          time.Time     `json:"time"`
+	Errors        []string      `json:"errors,omitempty"`
+}
+
+// Manifest returns the attestation of the model, stamped with the current
+// time. Package and Type are empty for models not built by reflection.
+func (m ModelInfo) Manifest() (Manifest, error) {
+	h, err := describeHasher(m.hasher())
+	if err != nil {
+		return Manifest{}, err
+	}
+	result := Manifest{
+		Model:         m.string,
+		Hash:          m.HashHex(),
+		Fingerprint:   m.Fingerprint(),
+		Hasher:        h,
+		FormatVersion: m.FormatVersion(),
+		GoVersion:     runtime.Version(),
+		Time:          time.Now().UTC(),
+	}
+	if m.root != nil && m.root.Type != nil {
+		result.Package = m.root.Type.PkgPath()
+		result.Type = m.root.Type.Name()
+	}
+	for _, err := range m.Errs {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	return result, nil
+}
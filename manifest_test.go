@@ -0,0 +1,31 @@
+package model_reflect_test
+
+import (
+	"encoding/json"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestManifest(t *testing.T) {
+	model, _ := model_reflect.New(&point{})
+	manifest, err := model.Manifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Model != model.String() || manifest.Hash != model.HashHex() ||
+		manifest.Fingerprint != model.Fingerprint() || manifest.Hasher.Algorithm != "argon2id" ||
+		manifest.Package != "github.com/go-modern/model_reflect_test" || manifest.Type != "point" ||
+		manifest.GoVersion != runtime.Version() || manifest.Time.IsZero() || manifest.FormatVersion != 1 {
+		t.Errorf("manifest: %+v", manifest)
+	}
+	if _, err := json.Marshal(manifest); err != nil {
+		t.Error(err)
+	}
+	keyed, _ := model_reflect.New(point{}, model_reflect.WithHasher(model_reflect.HMACHasher{Key: []byte("k")}))
+	if _, err := keyed.Manifest(); !errors.Is(err, model_reflect.ErrUnknownHasher) {
+		t.Errorf("keyed: %v", err)
+	}
+}
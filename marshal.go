@@ -0,0 +1,324 @@
+package model_reflect
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrUnknownHasher is returned when marshaling a model whose hasher is
+	// not one of the hashers of this package, or uses a custom hash function.
+	ErrUnknownHasher = errors.New("unknown hasher")
+	// ErrHashMismatch is returned when an unmarshaled model does not hash to
+	// its recorded hash.
+	ErrHashMismatch = errors.New("hash mismatch")
+	// ErrInvalidBinary is returned by UnmarshalBinary for malformed or
+	// unsupported data.
+	ErrInvalidBinary = errors.New("invalid binary model")
+)
+
+type (
+	modelJSON struct {
+		Model  string        `json:"model"`
+		Hash   string        `json:"hash,omitempty"`
+		Hasher *HasherParams `json:"hasher,omitempty"`
+		Errors []string      `json:"errors,omitempty"`
+	}
+
+	// HasherParams describes a hasher of this package for storage.
+	HasherParams struct {
+		Algorithm  string `json:"algorithm"`
+		Salt       []byte `json:"salt,omitempty"`
+		Time       uint32 `json:"time,omitempty"`
+		Memory     uint32 `json:"memory,omitempty"`
+		Threads    uint8  `json:"threads,omitempty"`
+		N          int    `json:"n,omitempty"`
+		R          int    `json:"r,omitempty"`
+		P          int    `json:"p,omitempty"`
+		Info       []byte `json:"info,omitempty"`
+		Iterations int    `json:"iterations,omitempty"`
+	}
+)
+
+const (
+	algorithmArgon2id = "argon2id"
+	algorithmSHA256   = "sha256"
+	algorithmScrypt   = "scrypt"
+	algorithmHKDF     = "hkdf-sha256"
+	algorithmPBKDF2   = "pbkdf2-sha256"
+)
+
+// binaryVersion is the version byte leading the binary encoding.
+const binaryVersion = 1
+
+// binaryAlgorithms numbers the hasher algorithms in the binary encoding.
+var binaryAlgorithms = []string{
+	algorithmArgon2id, algorithmSHA256, algorithmScrypt, algorithmHKDF, algorithmPBKDF2,
+}
+
+// describeHasher returns the parameters of h.
+func describeHasher(h Hasher) (*HasherParams, error) {
+	switch h := h.(type) {
+	case HashInfo:
+		return &HasherParams{
+			Algorithm: algorithmArgon2id, Salt: h.Salt, Time: h.Time, Memory: h.Memory, Threads: h.Threads,
+		}, nil
+	case *HashInfo:
+		if h == nil {
+			return nil, fmt.Errorf("%w: nil *HashInfo", ErrUnknownHasher)
+		}
+		return describeHasher(*h)
+	case sha256Hasher:
+		return &HasherParams{Algorithm: algorithmSHA256}, nil
+	case ScryptHasher:
+		return &HasherParams{Algorithm: algorithmScrypt, Salt: h.Salt, N: h.N, R: h.R, P: h.P}, nil
+	case HKDFHasher:
+		if h.Hash == nil {
+			return &HasherParams{Algorithm: algorithmHKDF, Salt: h.Salt, Info: h.Info}, nil
+		}
+	case PBKDF2Hasher:
+		if h.Hash == nil {
+			return &HasherParams{Algorithm: algorithmPBKDF2, Salt: h.Salt, Iterations: h.Iterations}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %T", ErrUnknownHasher, h)
+}
+
+// hasher returns the Hasher described by h.
+func (h *HasherParams) hasher() (Hasher, error) {
+	switch h.Algorithm {
+	case algorithmArgon2id:
+		info := HashInfo{Salt: h.Salt, Time: h.Time, Memory: h.Memory, Threads: h.Threads}
+		return info, info.Validate()
+	case algorithmSHA256:
+		return SHA256, nil
+	case algorithmScrypt:
+		scrypt := ScryptHasher{Salt: h.Salt, N: h.N, R: h.R, P: h.P}
+		return scrypt, scrypt.Validate()
+	case algorithmHKDF:
+		return HKDFHasher{Salt: h.Salt, Info: h.Info}, nil
+	case algorithmPBKDF2:
+		pbkdf2 := PBKDF2Hasher{Salt: h.Salt, Iterations: h.Iterations}
+		return pbkdf2, pbkdf2.Validate()
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownHasher, h.Algorithm)
+}
+
+// MarshalJSON encodes the canonical string, hash, hasher parameters and
+// error messages of the model.
+func (m ModelInfo) MarshalJSON() ([]byte, error) {
+	h, err := describeHasher(m.hasher())
+	if err != nil {
+		return nil, err
+	}
+	v := modelJSON{Model: m.string, Hash: m.HashHex(), Hasher: h}
+	for _, err := range m.Errs {
+		v.Errors = append(v.Errors, err.Error())
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes a model encoded by MarshalJSON. The model tree is
+// rebuilt with Parse, errors are restored as plain messages, and a recorded
+// hash must match the decoded model.
+func (m *ModelInfo) UnmarshalJSON(data []byte) error {
+	v := modelJSON{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	result := ModelInfo{string: v.Model, Hasher: DefaultHasher, memo: &hashMemo{}}
+	if v.Hasher != nil {
+		h, err := v.Hasher.hasher()
+		if err != nil {
+			return err
+		}
+		result.Hasher = h
+	}
+	root, err := Parse(v.Model)
+	if err != nil {
+		return err
+	}
+	result.root = root
+	for _, msg := range v.Errors {
+		result.Errs = append(result.Errs, errors.New(msg))
+	}
+	if v.Hash != "" && v.Hash != result.HashHex() {
+		return fmt.Errorf("%w: recorded %s, computed %s", ErrHashMismatch, v.Hash, result.HashHex())
+	}
+	*m = result
+	return nil
+}
+
+// MarshalText returns the canonical string of the model.
+func (m ModelInfo) MarshalText() ([]byte, error) {
+	return []byte(m.string), nil
+}
+
+// UnmarshalText parses a canonical string into a model using the default
+// hasher.
+func (m *ModelInfo) UnmarshalText(text []byte) error {
+	root, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*m = ModelInfo{string: string(text), Hasher: DefaultHasher, root: root, memo: &hashMemo{}}
+	return nil
+}
+
+// MarshalBinary encodes the model in a compact versioned format: the
+// version byte, the hasher algorithm and parameters, the canonical string
+// and the error messages.
+func (m ModelInfo) MarshalBinary() ([]byte, error) {
+	h, err := describeHasher(m.hasher())
+	if err != nil {
+		return nil, err
+	}
+	b := []byte{binaryVersion}
+	for i, a := range binaryAlgorithms {
+		if a == h.Algorithm {
+			b = append(b, byte(i))
+		}
+	}
+	switch h.Algorithm {
+	case algorithmArgon2id:
+		b = binary.AppendUvarint(b, uint64(h.Time))
+		b = binary.AppendUvarint(b, uint64(h.Memory))
+		b = append(b, h.Threads)
+		b = appendBytes(b, h.Salt)
+	case algorithmScrypt:
+		b = binary.AppendUvarint(b, uint64(h.N))
+		b = binary.AppendUvarint(b, uint64(h.R))
+		b = binary.AppendUvarint(b, uint64(h.P))
+		b = appendBytes(b, h.Salt)
+	case algorithmHKDF:
+		b = appendBytes(b, h.Salt)
+		b = appendBytes(b, h.Info)
+	case algorithmPBKDF2:
+		b = binary.AppendUvarint(b, uint64(h.Iterations))
+		b = appendBytes(b, h.Salt)
+	}
+	b = appendBytes(b, []byte(m.string))
+	b = binary.AppendUvarint(b, uint64(len(m.Errs)))
+	for _, err := range m.Errs {
+		b = appendBytes(b, []byte(err.Error()))
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes a model encoded by MarshalBinary, rebuilding the
+// model tree with Parse.
+func (m *ModelInfo) UnmarshalBinary(data []byte) error {
+	r := binaryReader{data: data}
+	if version := r.byte(); version != binaryVersion {
+		return fmt.Errorf("%w: version %d", ErrInvalidBinary, version)
+	}
+	algorithm := int(r.byte())
+	if algorithm >= len(binaryAlgorithms) {
+		return fmt.Errorf("%w: algorithm %d", ErrInvalidBinary, algorithm)
+	}
+	h := HasherParams{Algorithm: binaryAlgorithms[algorithm]}
+	switch h.Algorithm {
+	case algorithmArgon2id:
+		h.Time = uint32(r.uvarintMax(math.MaxUint32))
+		h.Memory = uint32(r.uvarintMax(math.MaxUint32))
+		h.Threads = r.byte()
+		h.Salt = bytes.Clone(r.bytes())
+	case algorithmScrypt:
+		h.N = int(r.uvarintMax(math.MaxInt32))
+		h.R = int(r.uvarintMax(math.MaxInt32))
+		h.P = int(r.uvarintMax(math.MaxInt32))
+		h.Salt = bytes.Clone(r.bytes())
+	case algorithmHKDF:
+		h.Salt = bytes.Clone(r.bytes())
+		h.Info = bytes.Clone(r.bytes())
+	case algorithmPBKDF2:
+		h.Iterations = int(r.uvarintMax(math.MaxInt32))
+		h.Salt = bytes.Clone(r.bytes())
+	}
+	result := ModelInfo{string: string(r.bytes()), memo: &hashMemo{}}
+	for n := r.uvarint(); n > 0 && r.err == nil; n-- {
+		result.Errs = append(result.Errs, errors.New(string(r.bytes())))
+	}
+	if r.err == nil && len(r.data) > 0 {
+		r.err = fmt.Errorf("%w: %d trailing bytes", ErrInvalidBinary, len(r.data))
+	}
+	if r.err != nil {
+		return r.err
+	}
+	hasher, err := h.hasher()
+	if err != nil {
+		return err
+	}
+	result.Hasher = hasher
+	if result.root, err = Parse(result.string); err != nil {
+		return err
+	}
+	*m = result
+	return nil
+}
+
+func appendBytes(b, data []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// binaryReader consumes the binary encoding, keeping the first error.
+type binaryReader struct {
+	data []byte
+	err  error
+}
+
+func (r *binaryReader) fail() {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: truncated", ErrInvalidBinary)
+	}
+	r.data = nil
+}
+
+func (r *binaryReader) byte() byte {
+	if len(r.data) == 0 {
+		r.fail()
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+// uvarintMax reads a uvarint, failing for values above limit.
+func (r *binaryReader) uvarintMax(limit uint64) uint64 {
+	v := r.uvarint()
+	if v > limit {
+		if r.err == nil {
+			r.err = fmt.Errorf("%w: %d out of range", ErrInvalidBinary, v)
+		}
+		r.data = nil
+		return 0
+	}
+	return v
+}
+
+func (r *binaryReader) bytes() []byte {
+	n := r.uvarint()
+	if n > uint64(len(r.data)) {
+		r.fail()
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
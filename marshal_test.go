@@ -0,0 +1,162 @@
+package model_reflect_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	for _, opts := range [][]model_reflect.Option{
+		nil,
+		{model_reflect.WithHasher(model_reflect.SHA256)},
+		{model_reflect.WithHasher(model_reflect.HashInfo{Salt: []byte("salt"), Time: 2, Memory: 16, Threads: 1})},
+	} {
+		model, err := model_reflect.New((*testA)(nil), opts...)
+		data, jerr := json.Marshal(model)
+		if jerr != nil {
+			t.Fatal(jerr)
+		}
+		decoded := model_reflect.ModelInfo{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+		if decoded.String() != model.String() || decoded.Hash() != model.Hash() ||
+			decoded.Model() == nil || errors.Join(decoded.Errs...).Error() != err.Error() {
+			t.Errorf("round trip: %s", data)
+		}
+	}
+}
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+	model, _ := model_reflect.New(point{})
+	data, _ := json.Marshal(model)
+	tampered := strings.Replace(string(data), "Y:int", "Y:string", 1)
+	if err := json.Unmarshal([]byte(tampered), &model_reflect.ModelInfo{}); !errors.Is(err, model_reflect.ErrHashMismatch) {
+		t.Errorf("tampered: %v", err)
+	}
+	invalid := strings.Replace(string(data), `"time":1`, `"time":0`, 1)
+	if err := json.Unmarshal([]byte(invalid), &model_reflect.ModelInfo{}); !errors.Is(err, model_reflect.ErrInvalidHasher) {
+		t.Errorf("invalid hasher: %v %s", err, invalid)
+	}
+	custom, _ := model_reflect.New(point{}, model_reflect.WithHasher(model_reflect.HasherFunc(
+		func(model []byte, size int) []byte { return make([]byte, size) })))
+	if _, err := json.Marshal(custom); !errors.Is(err, model_reflect.ErrUnknownHasher) {
+		t.Errorf("custom hasher: %v", err)
+	}
+	for _, params := range []string{
+		`{"algorithm":"scrypt","n":3,"r":8,"p":1}`,
+		`{"algorithm":"scrypt","n":1024,"r":-1,"p":1}`,
+		`{"algorithm":"scrypt","n":1024,"r":1073741824,"p":1}`,
+		`{"algorithm":"pbkdf2-sha256"}`,
+	} {
+		data := `{"model":"{ X:int, Y:int }","hasher":` + params + `}`
+		if err := json.Unmarshal([]byte(data), &model_reflect.ModelInfo{}); !errors.Is(err, model_reflect.ErrInvalidHasher) {
+			t.Errorf("invalid kdf %s: %v", params, err)
+		}
+	}
+	var nilInfo *model_reflect.HashInfo
+	custom.Hasher = nilInfo
+	if _, err := json.Marshal(custom); !errors.Is(err, model_reflect.ErrUnknownHasher) {
+		t.Errorf("nil *HashInfo: %v", err)
+	}
+	if _, err := custom.MarshalBinary(); !errors.Is(err, model_reflect.ErrUnknownHasher) {
+		t.Errorf("nil *HashInfo binary: %v", err)
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	model, _ := model_reflect.New(segment{})
+	text, err := model.MarshalText()
+	if err != nil || string(text) != model.String() {
+		t.Errorf("marshal: %s [%v]", text, err)
+	}
+	decoded := model_reflect.ModelInfo{}
+	if err := decoded.UnmarshalText(text); err != nil || decoded.Hash() != model.Hash() {
+		t.Errorf("unmarshal: %s [%v]", decoded, err)
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	for _, opts := range [][]model_reflect.Option{
+		nil,
+		{model_reflect.WithHasher(model_reflect.SHA256)},
+		{model_reflect.WithHasher(model_reflect.HashInfo{Salt: []byte("salt"), Time: 2, Memory: 16, Threads: 1})},
+	} {
+		model, err := model_reflect.New((*testA)(nil), opts...)
+		data, berr := model.MarshalBinary()
+		if berr != nil {
+			t.Fatal(berr)
+		}
+		decoded := model_reflect.ModelInfo{}
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%x: %v", data, err)
+		}
+		if decoded.String() != model.String() || decoded.Hash() != model.Hash() ||
+			decoded.Model() == nil || errors.Join(decoded.Errs...).Error() != err.Error() {
+			t.Errorf("round trip: %x", data)
+		}
+		for i := range data {
+			if err := decoded.UnmarshalBinary(data[:i]); !errors.Is(err, model_reflect.ErrInvalidBinary) {
+				t.Errorf("truncated at %d: %v", i, err)
+			}
+		}
+	}
+	model, _ := model_reflect.New(point{}, model_reflect.WithHasher(model_reflect.HashInfo{
+		Salt: []byte("salt"), Time: 1, Memory: 8, Threads: 1,
+	}))
+	data, _ := model.MarshalBinary()
+	decoded := model_reflect.ModelInfo{}
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for i := range data {
+		data[i] = 0
+	}
+	if salt := decoded.Hasher.(model_reflect.HashInfo).Salt; string(salt) != "salt" {
+		t.Errorf("salt shares the buffer: %q", salt)
+	}
+	data, _ = model.MarshalBinary()
+	data[0] = 99
+	if err := (&model_reflect.ModelInfo{}).UnmarshalBinary(data); !errors.Is(err, model_reflect.ErrInvalidBinary) {
+		t.Errorf("version: %v", err)
+	}
+	data = []byte{1, 0}
+	data = binary.AppendUvarint(data, 1<<32+1)
+	data = binary.AppendUvarint(data, 8)
+	data = append(data, 1, 0)
+	data = append(binary.AppendUvarint(data, uint64(len(model.String()))), model.String()...)
+	data = binary.AppendUvarint(data, 0)
+	if err := (&model_reflect.ModelInfo{}).UnmarshalBinary(data); !errors.Is(err, model_reflect.ErrInvalidBinary) ||
+		!strings.Contains(err.Error(), "out of range") {
+		t.Errorf("time out of range: %v", err)
+	}
+}
+
+func TestUnmarshalExport(t *testing.T) {
+	model, _ := model_reflect.New(exportTree{}, model_reflect.WithTypeNames(), model_reflect.WithCycleRefs())
+	want, err := model.Proto("x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(model)
+	decoded := model_reflect.ModelInfo{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := decoded.Proto("x"); err != nil || got != want {
+		t.Errorf("json: %s [%v]", got, err)
+	}
+	text, _ := model.MarshalText()
+	decoded = model_reflect.ModelInfo{}
+	if err := decoded.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := decoded.Proto("x"); err != nil || got != want {
+		t.Errorf("text: %s [%v]", got, err)
+	}
+}
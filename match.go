@@ -0,0 +1,42 @@
+package model_reflect
+
+import (
+	"fmt"
+)
+
+// MismatchError is returned by Match when a model does not have the
+// expected hash. It wraps ErrHashMismatch.
+type MismatchError struct {
+	Expected uint64
+	Actual   uint64
+	// Model is the current model, whose Pretty form the message shows.
+	Model ModelInfo
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%v: expected %#016x, got %#016x for model\n%s",
+		ErrHashMismatch, e.Expected, e.Actual, e.Model.Pretty())
+}
+
+// Unwrap returns ErrHashMismatch.
+func (e *MismatchError) Unwrap() error {
+	return ErrHashMismatch
+}
+
+// Match reflects v and returns a *MismatchError unless its hash is
+// expected. Reflection errors are part of the hash and are not reported.
+func Match(v any, expected uint64, opts ...Option) error {
+	m, _ := New(v, opts...)
+	if actual := m.Hash(); actual != expected {
+		return &MismatchError{Expected: expected, Actual: actual, Model: m}
+	}
+	return nil
+}
+
+// MustMatch is like Match but panics on mismatch, for init guards of
+// services with persisted data.
+func MustMatch(v any, expected uint64, opts ...Option) {
+	if err := Match(v, expected, opts...); err != nil {
+		panic(err)
+	}
+}
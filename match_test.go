@@ -0,0 +1,33 @@
+package model_reflect_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestMatch(t *testing.T) {
+	model, _ := model_reflect.New(point{})
+	if err := model_reflect.Match(point{}, model.Hash()); err != nil {
+		t.Errorf("match: %v", err)
+	}
+	err := model_reflect.Match(segment{}, model.Hash())
+	var mismatch *model_reflect.MismatchError
+	if !errors.Is(err, model_reflect.ErrHashMismatch) || !errors.As(err, &mismatch) ||
+		mismatch.Expected != model.Hash() || !strings.Contains(err.Error(), "  From: {\n") {
+		t.Errorf("mismatch: %v", err)
+	}
+}
+
+func TestMustMatch(t *testing.T) {
+	model, _ := model_reflect.New(point{})
+	model_reflect.MustMatch(point{}, model.Hash())
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, model_reflect.ErrHashMismatch) {
+			t.Errorf("panic: %v", err)
+		}
+	}()
+	model_reflect.MustMatch(point{}, model.Hash()+1)
+}
@@ -0,0 +1,51 @@
+package model_reflect
+
+import (
+	"encoding/binary"
+)
+
+// FieldHashes returns a hash for every top-level field of the model, keyed
+// by resolved field name. Comparing the field hashes of two models pinpoints
+// the fields that changed.
+func (m ModelInfo) FieldHashes() map[string]uint64 {
+	result := map[string]uint64{}
+	if m.root == nil || m.root.Kind != KindStruct {
+		return result
+	}
+	h := m.hasher()
+	for _, f := range m.root.Fields {
+		result[f.Name] = sum64(h, fieldLeaf(f))
+	}
+	return result
+}
+
+// MerkleHash returns the hash of the top-level field hashes in canonical
+// order. Models that are not structs hash like Hash. It is independent of
+// Hash, which stays a hash of the canonical string.
+func (m ModelInfo) MerkleHash() uint64 {
+	if m.root == nil || m.root.Kind != KindStruct {
+		return m.Hash()
+	}
+	h := m.hasher()
+	leaves := make([]byte, 0, 8*len(m.root.Fields))
+	for _, f := range m.root.Fields {
+		leaves = binary.LittleEndian.AppendUint64(leaves, sum64(h, fieldLeaf(f)))
+	}
+	return sum64(h, leaves)
+}
+
+// fieldLeaf returns the data hashed for a field: its name and model as
+// rendered in the struct, including the optional marker and annotation.
+func fieldLeaf(f *Model) []byte {
+	name := f.Name
+	if f.Optional {
+		name += "?"
+	}
+	return []byte(name + ":" + f.String() + f.Annotation)
+}
+
+func sum64(h Hasher, data []byte) uint64 {
+	var b [8]byte
+	copy(b[:], h.Sum(data, len(b)))
+	return binary.LittleEndian.Uint64(b[:])
+}
@@ -0,0 +1,42 @@
+package model_reflect_test
+
+import (
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestFieldHashes(t *testing.T) {
+	a, _ := model_reflect.New(orderV1{})
+	b, _ := model_reflect.New(orderV2{})
+	ha, hb := a.FieldHashes(), b.FieldHashes()
+	if len(ha) != 5 || ha["ID"] != hb["ID"] || ha["Status"] == hb["Status"] {
+		t.Errorf("field hashes: %v %v", ha, hb)
+	}
+	if a.MerkleHash() == b.MerkleHash() || a.MerkleHash() == a.Hash() {
+		t.Error("merkle hash")
+	}
+	scalar, _ := model_reflect.New(0)
+	if scalar.MerkleHash() != scalar.Hash() {
+		t.Error("scalar merkle hash")
+	}
+}
+
+func TestFieldHashesOptionality(t *testing.T) {
+	type required struct {
+		ID   int
+		Name string `json:"name"`
+	}
+	type optional struct {
+		ID   int
+		Name string `json:"name,omitempty"`
+	}
+	for _, opt := range []model_reflect.Option{model_reflect.WithOptionality(), model_reflect.WithTags("json")} {
+		a, _ := model_reflect.New(required{}, opt)
+		b, _ := model_reflect.New(optional{}, opt)
+		ha, hb := a.FieldHashes(), b.FieldHashes()
+		if ha["ID"] != hb["ID"] || ha["Name"] == hb["Name"] || a.MerkleHash() == b.MerkleHash() {
+			t.Errorf("%s / %s: %v %v", a, b, ha, hb)
+		}
+	}
+}
@@ -0,0 +1,543 @@
+package model_reflect
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Kind is the kind of a Model node.
+type Kind uint8
+
+const (
+	// KindNil is the model of a nil value.
+	KindNil Kind = iota
+	// KindLoop is a reference back to an enclosing type.
+	KindLoop
+	// KindOpaque is a type represented by the interfaces it implements.
+	KindOpaque
+	// KindUnknown is a type that cannot be represented.
+	KindUnknown
+	// KindLiteral is a field whose representation comes from its reflect tag.
+	KindLiteral
+	// KindScalar is a basic type such as int or string.
+	KindScalar
+	// KindSlice is a slice of Elem.
+	KindSlice
+	// KindArray is an array of Len Elem.
+	KindArray
+	// KindMap is a map from Key to Elem.
+	KindMap
+	// KindStruct is a struct with Fields.
+	KindStruct
+	// KindUnion is an interface with registered implementations as Variants.
+	KindUnion
+	// KindNamed is a type rendered by the name in Repr, such as a
+	// well-known type.
+	KindNamed
+	// KindTruncated is a type beyond the depth limit.
+	KindTruncated
+	// KindRef is a reference to a named struct type defined earlier in the
+	// model, with the reference in Repr.
+	KindRef
+	// KindFunc is a function with parameters In and results Out.
+	KindFunc
+	// KindChan is a channel of Elem in direction Dir.
+	KindChan
+	// KindCustom is a type described by an Extension, rendered as Repr
+	// with the models of its Args.
+	KindCustom
+	// KindNullable is an Elem that may be null, such as sql.NullString,
+	// rendered as ?string.
+	KindNullable
+)
+
+var kindNames = [...]string{
+	KindNil:       "nil",
+	KindLoop:      "loop",
+	KindOpaque:    "opaque",
+	KindUnknown:   "unknown",
+	KindLiteral:   "literal",
+	KindScalar:    "scalar",
+	KindSlice:     "slice",
+	KindArray:     "array",
+	KindMap:       "map",
+	KindStruct:    "struct",
+	KindUnion:     "union",
+	KindNamed:     "named",
+	KindTruncated: "truncated",
+	KindRef:       "ref",
+	KindFunc:      "func",
+	KindChan:      "chan",
+	KindCustom:    "custom",
+	KindNullable:  "nullable",
+}
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Model is one node of the reflected model tree. Struct fields are nodes of
+// their own: Name and the other field attributes are only set on them.
+type Model struct {
+	Kind Kind
+	// Repr is the scalar kind, the opaque interface list, the literal, the
+	// name of a named type or the back-reference of a loop.
+	Repr string
+	// Type is the Go type of the node, nil for models not built by reflection.
+	Type reflect.Type
+	// TypeName is rendered in front of the type when set, for example to
+	// name generic instantiations.
+	TypeName string
+	Nullable bool
+	Len      int
+	Key      *Model
+	Elem     *Model
+	Fields   []*Model
+	Variants []*Model
+	// In and Out are the parameters and results of a function; the last
+	// parameter is a slice when Variadic is set.
+	In       []*Model
+	Out      []*Model
+	Variadic bool
+	Dir      reflect.ChanDir
+	// Methods is the method set of the root node when recorded, each a
+	// KindFunc node with Name set.
+	Methods []*Model
+	// Args are the models of the child types of a KindCustom node.
+	Args []*Model
+	// Anchor labels a type that loops refer back to, rendered as &1 in
+	// front of it, with the loops rendered as *1.
+	Anchor int
+
+	// Name is the resolved field name, GoName the name of the Go field and
+	// WireName the name written by the encoders.
+	Name     string
+	GoName   string
+	WireName string
+	Tag      reflect.StructTag
+	Embedded bool
+	// Index is the index sequence of the field in the enclosing struct,
+	// longer than one for fields promoted from embedded structs.
+	Index []int
+	// Optional is set for fields that may be absent on the wire when
+	// optionality is recorded.
+	Optional bool
+	// Annotation holds the struct tags included in the canonical form.
+	Annotation string
+}
+
+// Model returns the root of the model tree.
+func (m ModelInfo) Model() *Model {
+	return m.root
+}
+
+// FromModel returns a ModelInfo for the given tree using the default hasher.
+func FromModel(root *Model) ModelInfo {
+	return ModelInfo{string: root.String(), Hasher: DefaultHasher, root: root, memo: &hashMemo{}}
+}
+
+// String returns the canonical representation of the model.
+func (n *Model) String() string {
+	buf := bufferPool.Get().(*buffer)
+	*buf = (*buf)[:0]
+	p := printer{w: buf}
+	p.write(n)
+	result := string(*buf)
+	if cap(*buf) <= maxPooledBuffer {
+		bufferPool.Put(buf)
+	}
+	return result
+}
+
+// buffer is a growable byte slice reused between renderings, so that String
+// allocates only its result.
+type buffer []byte
+
+func (b *buffer) WriteString(s string) (int, error) {
+	*b = append(*b, s...)
+	return len(s), nil
+}
+
+var bufferPool = sync.Pool{New: func() any { return &buffer{} }}
+
+// maxPooledBuffer keeps the buffers of exceptionally large models out of
+// the pool.
+const maxPooledBuffer = 1 << 20
+
+// Pretty returns the canonical representation with every struct field on
+// its own line, indented by two spaces per nesting level.
+func (n *Model) Pretty() string {
+	var b strings.Builder
+	p := printer{w: &b, pretty: true}
+	p.write(n)
+	return b.String()
+}
+
+// WriteTo writes the canonical representation of the model to w without
+// building it in memory first.
+func (n *Model) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	p := printer{w: bw}
+	p.write(n)
+	if p.err != nil {
+		return p.n, p.err
+	}
+	return p.n, bw.Flush()
+}
+
+// Pretty returns the indented representation of the model.
+func (m ModelInfo) Pretty() string {
+	if m.root == nil {
+		return m.string
+	}
+	return m.root.Pretty()
+}
+
+// WriteTo writes the canonical representation of the model to w.
+func (m ModelInfo) WriteTo(w io.Writer) (int64, error) {
+	if m.root == nil {
+		n, err := io.WriteString(w, m.string)
+		return int64(n), err
+	}
+	n, err := io.WriteString(w, formatPrefix(m.FormatVersion()))
+	if err != nil {
+		return int64(n), err
+	}
+	written, err := m.root.WriteTo(w)
+	return int64(n) + written, err
+}
+
+// printer renders model trees, keeping the first write error.
+type printer struct {
+	w      io.StringWriter
+	n      int64
+	err    error
+	pretty bool
+	depth  int
+	// structural leaves out field names and tags and orders fields by
+	// their rendering instead.
+	structural bool
+}
+
+func (p *printer) WriteString(s string) {
+	if p.err != nil {
+		return
+	}
+	n, err := p.w.WriteString(s)
+	p.n += int64(n)
+	p.err = err
+}
+
+// writeInt writes v in decimal, appending in place when rendering to a
+// buffer.
+func (p *printer) writeInt(v int) {
+	b, ok := p.w.(*buffer)
+	if !ok || p.err != nil {
+		p.WriteString(strconv.Itoa(v))
+		return
+	}
+	size := len(*b)
+	*b = strconv.AppendInt(*b, int64(v), 10)
+	p.n += int64(len(*b) - size)
+}
+
+func (p *printer) write(n *Model) {
+	if n == nil {
+		p.WriteString("<nil>")
+		return
+	}
+	if n.Anchor > 0 {
+		p.WriteString("&")
+		p.writeInt(n.Anchor)
+	}
+	if len(n.Methods) > 0 {
+		defer p.writeMethods(n)
+	}
+	if n.TypeName == "" {
+		p.writeType(n)
+		return
+	}
+	p.WriteString(n.TypeName)
+	if n.Kind == KindStruct {
+		p.writeType(n)
+		return
+	}
+	p.WriteString("(")
+	p.writeType(n)
+	p.WriteString(")")
+}
+
+func (p *printer) writeType(n *Model) {
+	switch n.Kind {
+	case KindNil:
+		p.WriteString("<nil>")
+	case KindLoop:
+		if n.Repr != "" {
+			p.WriteString(n.Repr)
+		} else {
+			p.WriteString("<...>")
+		}
+	case KindOpaque:
+		p.WriteString("<")
+		p.WriteString(n.Repr)
+		p.WriteString(">")
+	case KindUnknown:
+		p.WriteString("<?>")
+	case KindTruncated:
+		p.WriteString("<max-depth>")
+	case KindRef:
+		p.WriteString(n.Repr)
+	case KindSlice:
+		p.WriteString("[]")
+		p.write(n.Elem)
+	case KindArray:
+		p.WriteString("[")
+		p.writeInt(n.Len)
+		p.WriteString("]")
+		p.write(n.Elem)
+	case KindMap:
+		p.WriteString("map[")
+		p.write(n.Key)
+		p.WriteString("]")
+		p.write(n.Elem)
+	case KindStruct:
+		p.writeStruct(n)
+	case KindFunc:
+		p.writeFunc(n)
+	case KindChan:
+		switch n.Dir {
+		case reflect.SendDir:
+			p.WriteString("chan<- ")
+		case reflect.RecvDir:
+			p.WriteString("<-chan ")
+		default:
+			p.WriteString("chan ")
+		}
+		p.write(n.Elem)
+	case KindUnion:
+		p.WriteString("(")
+		for i, v := range n.Variants {
+			if i > 0 {
+				p.WriteString("|")
+			}
+			p.write(v)
+		}
+		p.WriteString(")")
+	case KindNullable:
+		p.WriteString("?")
+		p.write(n.Elem)
+	case KindCustom:
+		p.WriteString(n.Repr)
+		if len(n.Args) > 0 {
+			p.WriteString("<")
+			for i, a := range n.Args {
+				if i > 0 {
+					p.WriteString(", ")
+				}
+				p.write(a)
+			}
+			p.WriteString(">")
+		}
+	default:
+		p.WriteString(n.Repr)
+	}
+}
+
+func (p *printer) writeStruct(n *Model) {
+	if len(n.Fields) == 0 {
+		if p.pretty {
+			p.WriteString("{}")
+		} else {
+			p.WriteString("{  }")
+		}
+		return
+	}
+	if p.structural {
+		p.writeStructure(n)
+		return
+	}
+	p.WriteString("{")
+	p.depth++
+	for i, f := range n.Fields {
+		if i > 0 {
+			p.WriteString(",")
+		}
+		p.newline()
+		if !f.Embedded {
+			p.WriteString(f.Name)
+			if f.Optional {
+				p.WriteString("?")
+			}
+			p.WriteString(":")
+			if p.pretty {
+				p.WriteString(" ")
+			}
+		}
+		p.write(f)
+		p.WriteString(f.Annotation)
+	}
+	p.depth--
+	p.newline()
+	p.WriteString("}")
+}
+
+func (p *printer) writeMethods(n *Model) {
+	p.WriteString(" methods ")
+	p.writeStruct(&Model{Kind: KindStruct, Fields: n.Methods})
+}
+
+func (p *printer) writeFunc(n *Model) {
+	p.WriteString("func(")
+	for i, in := range n.In {
+		if i > 0 {
+			p.WriteString(", ")
+		}
+		if n.Variadic && i == len(n.In)-1 && in.Kind == KindSlice {
+			p.WriteString("...")
+			p.write(in.Elem)
+			continue
+		}
+		p.write(in)
+	}
+	p.WriteString(")")
+	switch len(n.Out) {
+	case 0:
+	case 1:
+		p.WriteString(" ")
+		p.write(n.Out[0])
+	default:
+		p.WriteString(" (")
+		for i, out := range n.Out {
+			if i > 0 {
+				p.WriteString(", ")
+			}
+			p.write(out)
+		}
+		p.WriteString(")")
+	}
+}
+
+// newline starts a new line in pretty mode and separates with a space
+// otherwise.
+func (p *printer) newline() {
+	if !p.pretty {
+		p.WriteString(" ")
+		return
+	}
+	p.WriteString("\n")
+	for i := 0; i < p.depth; i++ {
+		p.WriteString("  ")
+	}
+}
+
+// visit calls fn for n and its descendants in canonical order, skipping the
+// children of nodes for which fn returns false.
+func (n *Model) visit(fn func(*Model) bool) {
+	if n == nil || !fn(n) {
+		return
+	}
+	n.eachChild(func(c *Model) {
+		c.visit(fn)
+	})
+}
+
+// eachChild calls fn for the direct descendants of n in canonical order.
+func (n *Model) eachChild(fn func(*Model)) {
+	for _, c := range [...]*Model{n.Key, n.Elem} {
+		if c != nil {
+			fn(c)
+		}
+	}
+	for _, list := range [...][]*Model{n.Fields, n.Variants, n.Args, n.In, n.Out, n.Methods} {
+		for _, c := range list {
+			fn(c)
+		}
+	}
+}
+
+// anchorCycles labels every type that a loop refers back to with an anchor,
+// numbered in canonical order, and renders the loops as references to it.
+func anchorCycles(root *Model) {
+	targets := map[*Model]*Model{}
+	var mark func(n *Model, stack []*Model)
+	mark = func(n *Model, stack []*Model) {
+		if n.Kind == KindLoop && n.Type != nil {
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].Type == n.Type {
+					targets[n] = stack[i]
+					break
+				}
+			}
+			return
+		}
+		stack = append(stack, n)
+		n.eachChild(func(c *Model) {
+			mark(c, stack)
+		})
+	}
+	mark(root, nil)
+	anchored := map[*Model]bool{}
+	for _, target := range targets {
+		anchored[target] = true
+	}
+	next := 1
+	root.visit(func(n *Model) bool {
+		if anchored[n] {
+			n.Anchor = next
+			next++
+		}
+		return true
+	})
+	for loop, target := range targets {
+		loop.Repr = "*" + strconv.Itoa(target.Anchor)
+	}
+}
+
+// defineTypes rewrites the tree so that a named struct type used more than
+// once is defined by name at its first occurrence and referenced as @name
+// afterwards.
+func defineTypes(root *Model) {
+	label := func(n *Model) string {
+		if n.TypeName != "" {
+			return n.TypeName
+		}
+		return n.Type.Name()
+	}
+	named := func(n *Model) bool {
+		return n.Kind == KindStruct && n.Type != nil && n.Type.Name() != ""
+	}
+	counts := map[reflect.Type]int{}
+	root.visit(func(n *Model) bool {
+		if n.Kind == KindLoop && n.Type != nil {
+			counts[n.Type]++
+		}
+		if !named(n) {
+			return true
+		}
+		counts[n.Type]++
+		return counts[n.Type] == 1
+	})
+	defined := map[reflect.Type]bool{}
+	root.visit(func(n *Model) bool {
+		if !named(n) || counts[n.Type] < 2 {
+			return true
+		}
+		if !defined[n.Type] {
+			defined[n.Type] = true
+			n.TypeName = label(n)
+			return true
+		}
+		n.Kind = KindRef
+		n.Repr = "@" + label(n)
+		n.Fields = nil
+		return false
+	})
+}
@@ -1,13 +1,19 @@
 package model_reflect
 
 import (
+	"crypto/sha256"
 	"encoding"
+	"encoding/base32"
+	"encoding/base64"
 	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"golang.org/x/crypto/argon2"
 	"golang.org/x/exp/slices"
@@ -18,10 +24,29 @@ type (
 	ModelInfo struct {
 		string
 		Errs   []error
-		Hasher HashInfo
+		Hasher Hasher
+
+		root     *Model
+		warnings []error
+		memo     *hashMemo
+	}
+
+	// Hasher computes a digest of size bytes over a canonical model.
+	Hasher interface {
+		Sum(model []byte, size int) []byte
 	}
 
-	// HashInfo contains information about the hasher.
+	// NamespaceHasher is implemented by hashers that can be salted for a
+	// namespace, as CompiledModel.HashNamespace does.
+	NamespaceHasher interface {
+		Hasher
+		Namespace(namespace string) Hasher
+	}
+
+	// HasherFunc adapts an ordinary function to the Hasher interface.
+	HasherFunc func(model []byte, size int) []byte
+
+	// HashInfo contains the argon2id parameters of the default hasher.
 	HashInfo struct {
 		Salt    []byte
 		Time    uint32
@@ -39,6 +64,9 @@ var (
 	}
 
 	// DefaultInterfaces is the default list of interfaces to check.
+	//
+	// Deprecated: Modifying DefaultInterfaces is not safe for concurrent use.
+	// Use WithInterfaces or a Config instead.
 	DefaultInterfaces = []reflect.Type{
 		reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem(),
 		reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem(),
@@ -52,7 +80,16 @@ var (
 	ErrEmptyStruct = errors.New("empty struct")
 	// ErrDuplicate is returned when a struct has duplicate fields.
 	ErrDuplicate = errors.New("duplicate fields")
+	// ErrMaxDepth is returned when a model is deeper than the depth limit.
+	ErrMaxDepth = errors.New("max depth exceeded")
+	// ErrInvalidHasher is returned by SafeHash for hasher parameters that
+	// cannot produce a digest.
+	ErrInvalidHasher = errors.New("invalid hasher parameters")
 
+	// DefaultNameTags is the default ordered list of tags used for field names.
+	//
+	// Deprecated: Modifying DefaultNameTags is not safe for concurrent use.
+	// Use WithNameTags or a Config instead.
 	DefaultNameTags = []string{
 		"json",
 		"msgpack",
@@ -60,12 +97,52 @@ var (
 	}
 )
 
-// New returns a new ModelInfo.
-func New(v any) (m ModelInfo, err error) {
-	errs := []error{}
-	m = ModelInfo{Hasher: DefaultHasher}
-	m.string = typeToString(reflect.TypeOf(v), nil, &errs)
-	errs = uniqueErrors(errs)
+// PathError records an error and the path of the field it occurred at, as
+// in "Items[].Meta". Every error reported by New is a PathError; use
+// errors.As to extract the path.
+type PathError struct {
+	Path string
+	Err  error
+}
+
+func (e *PathError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// New returns a new ModelInfo. Options override the package defaults for
+// this call only.
+//
+// Results are cached per type and option set, so the returned Model tree is
+// shared between calls and must not be modified.
+func New(v any, opts ...Option) (m ModelInfo, err error) {
+	c := newConfig(opts...)
+	c.setValue(reflect.ValueOf(v))
+	return c.newModel(reflect.TypeOf(v))
+}
+
+// NewFromType is like New for a value of type t. A nil t yields the model
+// of a nil value.
+func NewFromType(t reflect.Type, opts ...Option) (ModelInfo, error) {
+	return newConfig(opts...).newModel(t)
+}
+
+func (c *config) newModel(t reflect.Type) (m ModelInfo, err error) {
+	m = ModelInfo{Hasher: c.hasher, memo: &hashMemo{}}
+	var findings []error
+	m.root, m.string, findings = c.reflect(t)
+	if err := c.cancelled(); err != nil {
+		return ModelInfo{}, err
+	}
+	errs, warnings := c.classify(findings)
+	m.warnings = warnings
 	if len(errs) > 0 {
 		m.Errs = errs
 		err = errors.Join(errs...)
@@ -73,17 +150,181 @@ func New(v any) (m ModelInfo, err error) {
 	return
 }
 
-// Hash returns a hash of the model.
+// NewFromValue is like New(v.Interface()) without requiring v to be
+// exported: interfaces are reflected by their dynamic type and the zero
+// Value or a nil interface yield the model of a nil value.
+func NewFromValue(v reflect.Value, opts ...Option) (ModelInfo, error) {
+	for v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return NewFromType(nil, opts...)
+	}
+	c := newConfig(opts...)
+	c.setValue(v)
+	return c.newModel(v.Type())
+}
+
+// Hash returns a short 64-bit hash of the model. Use Hash256 where
+// collisions matter.
+// The result is memoized for the hasher it was computed with.
 func (m ModelInfo) Hash() uint64 {
-	return binary.LittleEndian.Uint64(
-		argon2.IDKey(
-			[]byte(m.string),
-			m.Hasher.Salt,
-			m.Hasher.Time,
-			m.Hasher.Memory,
-			m.Hasher.Threads,
-			8,
-		))
+	h := m.hasher()
+	if m.memo == nil {
+		return sum64(h, []byte(m.string))
+	}
+	if hash, ok := m.memo.load(h); ok {
+		return hash
+	}
+	hash := sum64(h, []byte(m.string))
+	m.memo.store(h, hash)
+	return hash
+}
+
+// SetHasher replaces the hasher of m. Unlike assigning Hasher it also
+// detaches m from the memoized hash shared with its copies.
+func (m *ModelInfo) SetHasher(h Hasher) {
+	m.Hasher = h
+	m.memo = &hashMemo{}
+}
+
+// hashMemo holds the last Hash of a model together with its hasher. It is
+// shared by the copies of a ModelInfo, which all have the same canonical
+// form.
+type hashMemo struct {
+	mu     sync.Mutex
+	hasher Hasher
+	hash   uint64
+}
+
+// load returns the memoized hash if it was computed with a hasher equal to
+// h. Hashers that cannot be compared, such as HasherFunc, never match.
+func (memo *hashMemo) load(h Hasher) (uint64, bool) {
+	memo.mu.Lock()
+	defer memo.mu.Unlock()
+	if memo.hasher == nil || !reflect.DeepEqual(memo.hasher, h) {
+		return 0, false
+	}
+	return memo.hash, true
+}
+
+func (memo *hashMemo) store(h Hasher, hash uint64) {
+	memo.mu.Lock()
+	defer memo.mu.Unlock()
+	memo.hasher, memo.hash = h, hash
+}
+
+// SafeHash is like Hash but returns ErrInvalidHasher instead of panicking
+// when the hasher's parameters are invalid. Hashers with a Validate method,
+// such as HashInfo, are validated first.
+func (m ModelInfo) SafeHash() (hash uint64, err error) {
+	h := m.hasher()
+	if v, ok := h.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return 0, err
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrInvalidHasher, r)
+		}
+	}()
+	return sum64(h, []byte(m.string)), nil
+}
+
+// Hash256 returns a full-width 256-bit hash of the model.
+func (m ModelInfo) Hash256() (h [32]byte) {
+	copy(h[:], m.hasher().Sum([]byte(m.string), len(h)))
+	return
+}
+
+// HashHex returns Hash as 16 lower-case hex digits.
+func (m ModelInfo) HashHex() string {
+	return fmt.Sprintf("%016x", m.Hash())
+}
+
+// HashBase64 returns the big-endian bytes of Hash in unpadded URL-safe
+// base64, usable in file names and HTTP headers.
+func (m ModelInfo) HashBase64() string {
+	return base64.RawURLEncoding.EncodeToString(binary.BigEndian.AppendUint64(nil, m.Hash()))
+}
+
+// ShortID returns the top 40 bits of Hash as 8 lower-case base32 characters,
+// in Crockford's alphabet, for log lines and metric labels. It is not
+// collision resistant.
+func (m ModelInfo) ShortID() string {
+	return shortIDEncoding.EncodeToString(binary.BigEndian.AppendUint64(nil, m.Hash())[:5])
+}
+
+var shortIDEncoding = base32.NewEncoding("0123456789abcdefghjkmnpqrstvwxyz").WithPadding(base32.NoPadding)
+
+// Fingerprint returns Hash256 as lower-case hex, for places where the
+// short hash is not collision resistant enough.
+func (m ModelInfo) Fingerprint() string {
+	h := m.Hash256()
+	return hex.EncodeToString(h[:])
+}
+
+func (m ModelInfo) hasher() Hasher {
+	if m.Hasher == nil {
+		return DefaultHasher
+	}
+	return m.Hasher
+}
+
+// Sum calls f(model, size).
+func (f HasherFunc) Sum(model []byte, size int) []byte {
+	return f(model, size)
+}
+
+// Sum returns the argon2id key of the model.
+func (h HashInfo) Sum(model []byte, size int) []byte {
+	return argon2.IDKey(model, h.Salt, h.Time, h.Memory, h.Threads, uint32(size))
+}
+
+// Validate reports whether Sum can derive a key with the parameters of h:
+// Time and Threads must be positive and Memory at least 8 KiB per thread.
+func (h HashInfo) Validate() error {
+	switch {
+	case h.Time < 1:
+		return fmt.Errorf("%w: argon2 time %d", ErrInvalidHasher, h.Time)
+	case h.Threads < 1:
+		return fmt.Errorf("%w: argon2 threads %d", ErrInvalidHasher, h.Threads)
+	case h.Memory < 8*uint32(h.Threads):
+		return fmt.Errorf("%w: argon2 memory %d KiB for %d threads", ErrInvalidHasher, h.Memory, h.Threads)
+	}
+	return nil
+}
+
+// WithNamespace returns a copy of h whose salt is the SHA-256 of namespace,
+// giving every namespace its own hash space without managing salt bytes.
+func (h HashInfo) WithNamespace(namespace string) HashInfo {
+	h.Salt = namespaceSalt(namespace)
+	return h
+}
+
+// Namespace returns h.WithNamespace(namespace).
+func (h HashInfo) Namespace(namespace string) Hasher {
+	return h.WithNamespace(namespace)
+}
+
+func namespaceSalt(namespace string) []byte {
+	sum := sha256.Sum256([]byte(namespace))
+	return sum[:]
+}
+
+// SHA256 is a Hasher using SHA-256. Digests shorter than 32 bytes are
+// truncated.
+var SHA256 Hasher = sha256Hasher{}
+
+type sha256Hasher struct{}
+
+func (sha256Hasher) Sum(model []byte, size int) []byte {
+	sum := sha256.Sum256(model)
+	if size < len(sum) {
+		return sum[:size]
+	}
+	return sum[:]
 }
 
 // String returns a string representation of the model.
@@ -91,16 +332,17 @@ func (m ModelInfo) String() string {
 	return m.string
 }
 
-func uniqueErrors(slice []error) []error {
-	keys := map[string]bool{}
-	list := []error{}
-	for _, entry := range slice {
-		if _, ok := keys[entry.Error()]; !ok {
-			keys[entry.Error()] = true
-			list = append(list, entry)
-		}
-	}
-	return list
+// Equal reports whether m and other have the same canonical form,
+// regardless of their hashers and errors.
+func (m ModelInfo) Equal(other ModelInfo) bool {
+	return m.string == other.string
+}
+
+// Identity returns the SHA-256 digest of the canonical form. Unlike Hash it
+// does not depend on the hasher, and unlike ModelInfo it is comparable, so
+// it can key maps of models.
+func (m ModelInfo) Identity() [32]byte {
+	return sha256.Sum256([]byte(m.string))
 }
 
 func baseType(t reflect.Type) reflect.Type {
@@ -110,10 +352,13 @@ func baseType(t reflect.Type) reflect.Type {
 	return t
 }
 
-func checkInterfaces(t reflect.Type) []string {
+func (c *config) checkInterfaces(t reflect.Type) []string {
 	result := []string{}
-	for _, iface := range DefaultInterfaces {
-		if reflect.PtrTo(t).Implements(iface) {
+	for _, iface := range c.interfaces {
+		switch {
+		case c.receivers && !t.Implements(iface) && reflect.PtrTo(t).Implements(iface):
+			result = append(result, "*"+iface.String())
+		case reflect.PtrTo(t).Implements(iface):
 			result = append(result, iface.String())
 		}
 	}
@@ -121,134 +366,440 @@ func checkInterfaces(t reflect.Type) []string {
 	return result
 }
 
-func isConcrete(t reflect.Type) ([]string, bool) {
-	interfaces := checkInterfaces(t)
+func (c *config) isConcrete(t reflect.Type) ([]string, bool) {
+	if _, ok := c.types[t]; ok {
+		return nil, true
+	}
+	interfaces := c.checkInterfaces(t)
 	if len(interfaces) > 0 {
 		return interfaces, true
 	}
 	switch t.Kind() {
-	case reflect.Pointer, reflect.UnsafePointer, reflect.Interface, reflect.Func, reflect.Chan:
+	case reflect.Interface:
+		return nil, len(c.impls[t]) > 0 || c.values
+	case reflect.Func:
+		return nil, c.funcs
+	case reflect.Chan:
+		return nil, c.chans
+	case reflect.UnsafePointer:
+		return nil, c.pointers >= PointerPlaceholder
+	case reflect.Uintptr:
+		return nil, c.pointers != PointerSkip
+	case reflect.Pointer:
 		return nil, false
 	default:
 		return nil, true
 	}
 }
 
-func expandField(f reflect.StructField, types []reflect.Type, result *[][]reflect.StructField) []error {
+func (c *config) expandField(f reflect.StructField, path []string, types []reflect.Type, result *[][]reflect.StructField) []error {
 	errs := []error{}
 	depth := len(types)
 	for depth >= len(*result) {
 		*result = append(*result, []reflect.StructField{})
 	}
+	if c.isIgnored(f) || c.fieldFilter != nil && !c.fieldFilter(f, append(path, c.getName(f))) {
+		return nil
+	}
 	t := baseType(f.Type)
+	flatten := c.isInline(f)
+	if f.Anonymous && t.Kind() == reflect.Struct && !flatten {
+		switch c.embedding {
+		case EmbedNest:
+			f.Anonymous = false
+		case EmbedSkip:
+			return nil
+		default:
+			flatten = true
+		}
+	}
 	idx := slices.Index(types, t)
 	if idx >= 0 {
-		errs = append(errs, fmt.Errorf("%w in %s", ErrLoopDetected, t))
+		if c.cycleRefs {
+			return nil
+		}
+		errs = append(errs, LoopError{Type: t})
 		return errs
 	}
 	types = append(types, t)
-	if t.Kind() != reflect.Struct || !f.Anonymous {
+	if t.Kind() != reflect.Struct || !flatten {
 		(*result)[depth] = append((*result)[depth], f)
 		return nil
 	}
 	n := t.NumField()
 	for i := 0; i < n; i++ {
-		errs = append(errs, expandField(t.Field(i), types, result)...)
+		child := t.Field(i)
+		child.Index = append(slices.Clone(f.Index), i)
+		errs = append(errs, c.expandField(child, path, types, result)...)
 	}
 	return errs
 }
 
-func getName(f reflect.StructField) string {
-	for _, tag := range DefaultNameTags {
+func (c *config) tagName(f reflect.StructField) string {
+	for _, tag := range c.nameTags {
 		name := strings.Split(f.Tag.Get(tag), ",")[0]
 		if name != "" {
-			return strings.ToUpper(name[0:1]) + name[1:]
+			return name
+		}
+	}
+	return ""
+}
+
+// tagOptions returns the options of the first name tag present on f.
+func (c *config) tagOptions(f reflect.StructField) []string {
+	for _, tag := range c.nameTags {
+		if v, ok := f.Tag.Lookup(tag); ok {
+			return strings.Split(v, ",")[1:]
 		}
 	}
+	return nil
+}
+
+// isIgnored reports whether the tag providing the name of f marks it as
+// never encoded, as in json:"-".
+func (c *config) isIgnored(f reflect.StructField) bool {
+	if !c.ignoreMarkers {
+		return false
+	}
+	for _, tag := range c.nameTags {
+		v := f.Tag.Get(tag)
+		if strings.Split(v, ",")[0] != "" {
+			return v == "-"
+		}
+	}
+	return false
+}
+
+// isOptional reports whether f may be absent on the wire.
+func (c *config) isOptional(f reflect.StructField) bool {
+	options := c.tagOptions(f)
+	return f.Type.Kind() == reflect.Pointer ||
+		slices.Contains(options, "omitempty") || slices.Contains(options, "omitzero")
+}
+
+// annotation returns the tags of f selected with WithTags.
+func (c *config) annotation(f reflect.StructField) string {
+	tags := []string{}
+	for _, tag := range c.hashTags {
+		if v, ok := f.Tag.Lookup(tag); ok {
+			tags = append(tags, tag+":"+strconv.Quote(v))
+		}
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	return "`" + strings.Join(tags, " ") + "`"
+}
+
+// isInline reports whether f is flattened into its parent by its tag.
+func (c *config) isInline(f reflect.StructField) bool {
+	if !c.inline {
+		return false
+	}
+	options := c.tagOptions(f)
+	return slices.Contains(options, "inline") || slices.Contains(options, "squash")
+}
+
+// wireName returns the field name as written by the encoders.
+func (c *config) wireName(f reflect.StructField) string {
+	if name := parseReflectTag(f).name; name != "" {
+		return name
+	}
+	if name := c.tagName(f); name != "" {
+		return name
+	}
+	return f.Name
+}
+
+func (c *config) getName(f reflect.StructField) string {
+	if name := parseReflectTag(f).name; name != "" {
+		return name
+	}
+	if name := c.tagName(f); name != "" {
+		return strings.ToUpper(name[0:1]) + name[1:]
+	}
 	return f.Name
 }
 
-func structFields(t reflect.Type) ([]reflect.StructField, []error) {
+// pathNames splits a field path such as "Items[].Meta" into field names.
+func pathNames(path string) []string {
+	if path == "" {
+		return nil
+	}
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		for strings.HasSuffix(name, "]") {
+			name = name[:strings.LastIndexByte(name, '[')]
+		}
+		names[i] = name
+	}
+	return slices.Clip(names)
+}
+
+func (c *config) structFields(t reflect.Type, path string) ([]reflect.StructField, []error) {
 	if t.Kind() != reflect.Struct {
 		return nil, nil
 	}
 	errs := []error{}
 	expand := [][]reflect.StructField{}
+	var names []string
+	if c.fieldFilter != nil {
+		names = pathNames(path)
+	}
 	n := t.NumField()
 	for i := 0; i < n; i++ {
-		errs = append(errs, expandField(t.Field(i), nil, &expand)...)
+		for _, err := range c.expandField(t.Field(i), names, nil, &expand) {
+			errs = append(errs, &PathError{Path: path, Err: err})
+		}
 	}
 	counts := map[string]int{}
 	result := []reflect.StructField{}
 	for i, level := range expand {
 		localCounts := map[string]int{}
 		for _, f := range level {
-			name := getName(f)
+			name := c.getName(f)
 			counts[name]++
 			localCounts[name]++
 		}
 		for _, f := range level {
-			if f.Tag.Get("reflect") == "-" {
+			if parseReflectTag(f).ignore {
 				continue
 			}
-			name := getName(f)
+			name := c.getName(f)
 			if counts[name] == 1 {
 				result = append(result, f)
 			}
 		}
 		for name, count := range localCounts {
 			if count > 1 {
-				errs = append(errs, fmt.Errorf("type %s (embed level %d): %w [%d]%s",
-					t, i, ErrDuplicate, count, name))
+				err := DuplicateError{Type: t, Level: i, Name: name, Count: count}
+				errs = append(errs, &PathError{Path: joinPath(path, name), Err: err})
 			}
 		}
 	}
 	return result, errs
 }
 
-func typeToString(t reflect.Type, types []reflect.Type, errs *[]error) string {
+func (c *config) typeToNode(t reflect.Type, types []reflect.Type, path string, errs *[]error) *Model {
+	if c.cancelled() != nil {
+		return &Model{Kind: KindTruncated}
+	}
 	if t == nil {
-		return "<nil>"
+		return &Model{Kind: KindNil}
 	}
+	nullable := t.Kind() == reflect.Pointer
 	t = baseType(t)
+	n := &Model{Type: t, Nullable: nullable}
+	switch {
+	case c.typeNames && t.PkgPath() != "":
+		n.TypeName = t.PkgPath() + "." + t.Name()
+	case c.typeArgs && strings.Contains(t.Name(), "["):
+		n.TypeName = t.Name()
+	case c.definedNames && t.PkgPath() != "" && t.Kind() != reflect.Struct && t.Kind() != reflect.Interface:
+		n.TypeName = t.Name()
+	}
 
 	idx := slices.Index(types, t)
 	if idx >= 0 {
-		*errs = append(*errs, fmt.Errorf("%w in %s", ErrLoopDetected, t))
-		return "<...>"
+		n.Kind = KindLoop
+		if c.cycleRefs {
+			n.Repr = "@" + t.Name()
+			return n
+		}
+		*errs = append(*errs, &PathError{Path: path, Err: LoopError{Type: t}})
+		return n
+	}
+	if c.maxDepth > 0 && len(types) >= c.maxDepth {
+		*errs = append(*errs, &PathError{Path: path, Err: ErrMaxDepth})
+		n.Kind = KindTruncated
+		return n
 	}
 	types = append(types, t)
 
-	interfaces, ok := isConcrete(t)
+	if name, ok := c.types[t]; ok {
+		n.Kind = KindNamed
+		n.Repr = name
+		return n
+	}
+	if c.typeTransform != nil {
+		if repr, ok := c.typeTransform(t); ok {
+			n.Kind = KindNamed
+			n.Repr = repr
+			return n
+		}
+	}
+	if f, ok := c.describe(t); ok {
+		n.Kind = KindCustom
+		n.Repr = f.Repr
+		for _, child := range f.Children {
+			n.Args = append(n.Args, c.typeToNode(child, types, path+"<>", errs))
+		}
+		return n
+	}
+	if t == durationType && c.durations != DurationDefault {
+		n.Kind, n.Repr = KindScalar, "int64"
+		switch c.durations {
+		case DurationName:
+			n.Kind, n.Repr = KindNamed, "duration"
+		case DurationString:
+			n.Repr = "string"
+		}
+		return n
+	}
+	if name, ok := c.wellKnownName(t); ok {
+		n.Kind = KindNamed
+		n.Repr = name
+		return n
+	}
+	if c.isMessage(t) {
+		n.Kind = KindNamed
+		n.Repr = t.String()
+		n.TypeName = "message"
+		return n
+	}
+
+	interfaces, ok := c.isConcrete(t)
 	if len(interfaces) > 0 {
-		return "<" + strings.Join(interfaces, ",") + ">"
+		n.Kind = KindOpaque
+		n.Repr = strings.Join(interfaces, ",")
+		return n
+	}
+	if c.sqlNulls && isSQLNull(t) {
+		n.Kind = KindNullable
+		n.Nullable = true
+		n.Elem = c.typeToNode(t.Field(0).Type, types, path, errs)
+		return n
 	}
 	if !ok {
-		return "<?>"
+		n.Kind = KindUnknown
+		if c.strict {
+			*errs = append(*errs, &PathError{Path: path, Err: fmt.Errorf("%w %s", ErrUnsupported, t)})
+		}
+		return n
+	}
+
+	if k := t.Kind(); (k == reflect.Uintptr || k == reflect.UnsafePointer) && c.pointers >= PointerPlaceholder {
+		n.Kind = KindOpaque
+		n.Repr = k.String()
+		if c.pointers == PointerError {
+			*errs = append(*errs, &PathError{Path: path, Err: fmt.Errorf("%w %s", ErrPointerField, t)})
+		}
+		return n
 	}
 
 	switch t.Kind() {
+	case reflect.Interface:
+		n.Kind = KindUnion
+		for _, impl := range c.impls[t] {
+			n.Variants = append(n.Variants, c.typeToNode(impl, types, path, errs))
+		}
+		sort.SliceStable(n.Variants, func(i, j int) bool {
+			return n.Variants[i].String() < n.Variants[j].String()
+		})
 	case reflect.Slice:
-		return fmt.Sprintf("[]%s",
-			typeToString(t.Elem(), types, errs),
-		)
+		n.Kind = KindSlice
+		n.Elem = c.typeToNode(t.Elem(), types, path+"[]", errs)
 	case reflect.Array:
-		return fmt.Sprintf("[%d]%s",
-			t.Len(),
-			typeToString(t.Elem(), types, errs),
-		)
+		n.Kind = KindArray
+		n.Len = t.Len()
+		if c.omitArrayLengths || c.byteStrings && t.Elem().Kind() == reflect.Uint8 {
+			n.Kind, n.Len = KindSlice, 0
+		}
+		n.Elem = c.typeToNode(t.Elem(), types, path+"[]", errs)
 	case reflect.Map:
-		return fmt.Sprintf("map[%s]%s",
-			typeToString(t.Key(), types, errs),
-			typeToString(t.Elem(), types, errs),
-		)
+		n.Kind = KindMap
+		n.Key = c.typeToNode(t.Key(), types, path+"[key]", errs)
+		n.Elem = c.typeToNode(t.Elem(), types, path+"[]", errs)
 	case reflect.Struct:
-		// continue
+		n.Kind = KindStruct
+		n.Fields = c.structNodes(t, types, path, errs)
+	case reflect.Chan:
+		n.Kind = KindChan
+		n.Dir = t.ChanDir()
+		n.Elem = c.typeToNode(t.Elem(), types, path+"[]", errs)
+	case reflect.Func:
+		c.signature(n, t, 0, types, path, errs)
 	default:
-		return t.Kind().String()
+		n.Kind = KindScalar
+		n.Repr = t.Kind().String()
+	}
+	return n
+}
+
+// isSQLNull reports whether t is one of the database/sql Null types, a
+// value followed by a Valid flag.
+func isSQLNull(t reflect.Type) bool {
+	return t.PkgPath() == "database/sql" && strings.HasPrefix(t.Name(), "Null") &&
+		t.Kind() == reflect.Struct && t.NumField() == 2 && t.Field(1).Name == "Valid"
+}
+
+// indexLess orders field index paths by declaration, placing promoted
+// fields where their embedded struct is declared.
+func indexLess(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// signature turns n into the KindFunc node of t, leaving out the first skip
+// parameters such as method receivers.
+func (c *config) signature(n *Model, t reflect.Type, skip int, types []reflect.Type, path string, errs *[]error) {
+	n.Kind = KindFunc
+	n.Variadic = t.IsVariadic()
+	for i := skip; i < t.NumIn(); i++ {
+		in := t.In(i)
+		if n.Variadic && i == t.NumIn()-1 {
+			elem := c.signatureNode(in.Elem(), types, path+"()", errs)
+			n.In = append(n.In, &Model{Kind: KindSlice, Type: in, Elem: elem})
+			continue
+		}
+		n.In = append(n.In, c.signatureNode(in, types, path+"()", errs))
+	}
+	for i := 0; i < t.NumOut(); i++ {
+		n.Out = append(n.Out, c.signatureNode(t.Out(i), types, path+"()", errs))
 	}
+}
 
-	fields, e := structFields(t)
+// methodNodes returns the exported methods of t, including those with a
+// pointer receiver, as KindFunc nodes named after the method.
+func (c *config) methodNodes(t reflect.Type, errs *[]error) []*Model {
+	skip := 0
+	if t.Kind() != reflect.Interface {
+		t, skip = reflect.PointerTo(t), 1
+	}
+	result := []*Model{}
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if !m.IsExported() {
+			continue
+		}
+		n := &Model{Type: m.Type, Name: m.Name, GoName: m.Name}
+		c.signature(n, m.Type, skip, []reflect.Type{baseType(t)}, m.Name, errs)
+		result = append(result, n)
+	}
+	return result
+}
+
+// signatureNode returns the node of a function parameter or result. Unlike
+// fields, interfaces without implementations such as error are kept by name.
+func (c *config) signatureNode(t reflect.Type, types []reflect.Type, path string, errs *[]error) *Model {
+	if t.Kind() == reflect.Interface {
+		if _, ok := c.isConcrete(t); !ok {
+			name := t.String()
+			if t.Name() == "" && t.NumMethod() == 0 {
+				name = "any"
+			}
+			return &Model{Kind: KindNamed, Repr: name, Type: t}
+		}
+	}
+	return c.typeToNode(t, types, path, errs)
+}
+
+func (c *config) structNodes(t reflect.Type, types []reflect.Type, path string, errs *[]error) []*Model {
+	fields, e := c.structFields(t, path)
 	if errs != nil && len(e) > 0 {
 		*errs = append(*errs, e...)
 	}
@@ -259,10 +810,12 @@ func typeToString(t reflect.Type, types []reflect.Type, errs *[]error) string {
 		if !f.IsExported() {
 			continue
 		}
-		if _, ok := isConcrete(baseType(f.Type)); !ok {
+		name := c.getName(f)
+		if _, ok := c.isConcrete(baseType(f.Type)); !ok {
+			err := fmt.Errorf("%w of type %s", ErrSkippedField, f.Type)
+			*errs = append(*errs, &PathError{Path: joinPath(path, name), Err: err})
 			continue
 		}
-		name := getName(f)
 		if f.Anonymous {
 			name = "." + name
 		}
@@ -271,26 +824,43 @@ func typeToString(t reflect.Type, types []reflect.Type, errs *[]error) string {
 		}
 		fieldMap[name] = f
 	}
-	sort.Strings(keys)
+	if c.declarationOrder {
+		sort.Slice(keys, func(i, j int) bool {
+			return indexLess(fieldMap[keys[i]].Index, fieldMap[keys[j]].Index)
+		})
+	} else {
+		sort.Strings(keys)
+	}
 
-	r := "{ "
-	n := len(keys)
-	if n == 0 {
-		*errs = append(*errs, fmt.Errorf("%w %s", ErrEmptyStruct, t))
+	if len(keys) == 0 {
+		*errs = append(*errs, &PathError{Path: path, Err: EmptyStructError{Type: t}})
 	}
-	for i, name := range keys {
+	result := make([]*Model, 0, len(keys))
+	for _, name := range keys {
 		f := fieldMap[name]
-		if !f.Anonymous {
-			r += name + ":"
+		var n *Model
+		tag := parseReflectTag(f)
+		switch {
+		case tag.literal != "" || tag.typ != "":
+			n = &Model{Kind: KindLiteral, Repr: tag.literal + tag.typ}
+		case tag.opaque:
+			n = &Model{Kind: KindOpaque, Repr: baseType(f.Type).String()}
+		default:
+			n = c.typeToNode(f.Type, types, joinPath(path, strings.TrimPrefix(name, ".")), errs)
 		}
-		if tag := f.Tag.Get("reflect"); tag != "" {
-			r += tag
-		} else {
-			r += typeToString(f.Type, types, errs)
-		}
-		if i < n-1 {
-			r += ", "
+		if n.Type == nil {
+			n.Type = baseType(f.Type)
+			n.Nullable = f.Type.Kind() == reflect.Pointer
 		}
+		n.Name = strings.TrimPrefix(name, ".")
+		n.GoName = f.Name
+		n.WireName = c.wireName(f)
+		n.Tag = f.Tag
+		n.Embedded = f.Anonymous
+		n.Index = f.Index
+		n.Optional = c.optionality && c.isOptional(f)
+		n.Annotation = c.annotation(f)
+		result = append(result, n)
 	}
-	return r + " }"
+	return result
 }
@@ -1,6 +1,10 @@
 package model_reflect_test
 
 import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -74,3 +78,211 @@ func TestModelReflectRecursive(t *testing.T) {
 		t.Error("test")
 	}
 }
+
+func TestModelReflectHash256(t *testing.T) {
+	model, _ := model_reflect.New((*testA)(nil), model_reflect.WithHasher(model_reflect.SHA256))
+	h := model.Hash256()
+	if binary.LittleEndian.Uint64(h[:8]) != model.Hash() {
+		t.Errorf("sha256 short form is not a prefix: %x %x", h, model.Hash())
+	}
+	model, _ = model_reflect.New((*testA)(nil))
+	if model.Hash256() == [32]byte{} {
+		t.Error("empty argon2 digest")
+	}
+}
+
+func TestModelReflectCycleRefs(t *testing.T) {
+	model, err := model_reflect.New((*testA)(nil), model_reflect.WithCycleRefs())
+	if err != nil || model.String() != "{ A:int, B:int, X:{ A:int, B:int, X:@testB } }" {
+		t.Errorf("TestModelReflectCycleRefs: %s [%v]", model, err)
+	}
+}
+
+type anchorTree struct {
+	Value    int
+	Children []anchorTree
+}
+
+type anchorA struct {
+	B    *anchorB
+	Name string
+}
+
+type anchorB struct {
+	A    *anchorA
+	Self *anchorB
+}
+
+type anchorOther struct {
+	Value    string
+	Children []anchorOther
+}
+
+func TestModelReflectAnchors(t *testing.T) {
+	for _, c := range []struct {
+		v    any
+		want string
+	}{
+		{anchorTree{}, "&1{ Children:[]*1, Value:int }"},
+		{anchorA{}, "&1{ B:&2{ A:*1, Self:*2 }, Name:string }"},
+		{point{}, "{ X:int, Y:int }"},
+	} {
+		model, err := model_reflect.New(c.v, model_reflect.WithAnchors())
+		if err != nil || model.String() != c.want {
+			t.Errorf("TestModelReflectAnchors: %s [%v]", model, err)
+		}
+	}
+	a, _ := model_reflect.New(anchorTree{}, model_reflect.WithAnchors())
+	b, _ := model_reflect.New(anchorOther{}, model_reflect.WithAnchors())
+	if a.Hash() == b.Hash() {
+		t.Errorf("TestModelReflectAnchors: %s and %s collide", a, b)
+	}
+}
+
+func TestModelReflectHashEncodings(t *testing.T) {
+	model, _ := model_reflect.New((*testStruct2)(nil))
+	if model.HashHex() != "405cb444929937ae" || model.HashBase64() != "QFy0RJKZN64" {
+		t.Errorf("TestModelReflectHashEncodings: %s %s", model.HashHex(), model.HashBase64())
+	}
+	h := model.Hash256()
+	if fp := model.Fingerprint(); len(fp) != 64 || fp != fmt.Sprintf("%x", h) {
+		t.Errorf("TestModelReflectHashEncodings: fingerprint %s", fp)
+	}
+	if id := model.ShortID(); id != "81eb8h4j" {
+		t.Errorf("TestModelReflectHashEncodings: short id %s", id)
+	}
+}
+
+type pathErrorStruct struct {
+	Items []struct {
+		Meta  struct{}
+		Inner struct{ *testA }
+	}
+}
+
+func TestModelReflectErrorPaths(t *testing.T) {
+	_, err := model_reflect.New(pathErrorStruct{})
+	paths := map[string]error{}
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var pathErr *model_reflect.PathError
+		if !errors.As(e, &pathErr) {
+			t.Fatalf("TestModelReflectErrorPaths: untyped error %v", e)
+		}
+		paths[pathErr.Path] = pathErr.Err
+	}
+	if !errors.Is(paths["Items[].Meta"], model_reflect.ErrEmptyStruct) {
+		t.Errorf("TestModelReflectErrorPaths: empty struct %v", err)
+	}
+	if !errors.Is(paths["Items[].Inner.X"], model_reflect.ErrLoopDetected) {
+		t.Errorf("TestModelReflectErrorPaths: loop %v", err)
+	}
+}
+
+func TestModelReflectNamespace(t *testing.T) {
+	orders := model_reflect.DefaultHasher.WithNamespace("orders-service")
+	if len(model_reflect.DefaultHasher.Salt) != 0 || len(orders.Salt) != 32 {
+		t.Fatalf("TestModelReflectNamespace: salt %x", orders.Salt)
+	}
+	a, _ := model_reflect.New(point{}, model_reflect.WithHasher(orders))
+	b, _ := model_reflect.New(point{}, model_reflect.WithHasher(model_reflect.DefaultHasher.WithNamespace("orders-service")))
+	c, _ := model_reflect.New(point{}, model_reflect.WithHasher(model_reflect.DefaultHasher.WithNamespace("billing")))
+	if a.Hash() != b.Hash() || a.Hash() == c.Hash() {
+		t.Errorf("TestModelReflectNamespace: %x %x %x", a.Hash(), b.Hash(), c.Hash())
+	}
+}
+
+func TestModelReflectFromType(t *testing.T) {
+	want, _ := model_reflect.New(point{})
+	holder := struct{ p any }{p: point{}}
+	byType, _ := model_reflect.NewFromType(reflect.TypeOf(point{}))
+	byValue, _ := model_reflect.NewFromValue(reflect.ValueOf(point{}))
+	byField, _ := model_reflect.NewFromValue(reflect.ValueOf(holder).Field(0))
+	byConfig, _ := model_reflect.NewConfig().NewFromType(reflect.TypeOf(point{}))
+	for i, got := range []model_reflect.ModelInfo{byType, byValue, byField, byConfig} {
+		if got.String() != want.String() || got.Hash() != want.Hash() {
+			t.Errorf("TestModelReflectFromType: %d %s", i, got)
+		}
+	}
+	null, _ := model_reflect.New(nil)
+	if got, _ := model_reflect.NewFromValue(reflect.Value{}); got.String() != null.String() {
+		t.Errorf("TestModelReflectFromType: zero value %s", got)
+	}
+}
+
+func TestModelReflectEqual(t *testing.T) {
+	a, _ := model_reflect.New(point{})
+	b, _ := model_reflect.New(&point{}, model_reflect.WithHasher(model_reflect.SHA256))
+	c, _ := model_reflect.New(segment{})
+	if !a.Equal(b) || a.Equal(c) || a.Hash() == b.Hash() {
+		t.Errorf("TestModelReflectEqual: %s %s %s", a, b, c)
+	}
+	seen := map[[32]byte]bool{a.Identity(): true}
+	if !seen[b.Identity()] || seen[c.Identity()] {
+		t.Errorf("TestModelReflectEqual: identity %x %x", a.Identity(), c.Identity())
+	}
+}
+
+type errorOrderStruct struct {
+	Z struct{}
+	A struct{ *testA }
+	M []struct{ N struct{} }
+}
+
+func TestModelReflectErrorOrder(t *testing.T) {
+	m, _ := model_reflect.New(errorOrderStruct{})
+	paths := []string{}
+	for _, err := range m.Errs {
+		var pathErr *model_reflect.PathError
+		if errors.As(err, &pathErr) {
+			paths = append(paths, pathErr.Path)
+		}
+	}
+	if want := []string{"A", "A.X", "A.X.X", "M[].N", "Z"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("TestModelReflectErrorOrder: %q", paths)
+	}
+}
+
+func TestModelReflectSafeHash(t *testing.T) {
+	model, _ := model_reflect.New(point{})
+	if h, err := model.SafeHash(); err != nil || h != model.Hash() {
+		t.Errorf("TestModelReflectSafeHash: %x [%v]", h, err)
+	}
+	for _, h := range []model_reflect.HashInfo{{Memory: 8, Threads: 1}, {Time: 1, Memory: 8}, {Time: 1, Memory: 8, Threads: 2}} {
+		model.Hasher = h
+		if _, err := model.SafeHash(); !errors.Is(err, model_reflect.ErrInvalidHasher) || h.Validate() == nil {
+			t.Errorf("TestModelReflectSafeHash: %+v [%v]", h, err)
+		}
+	}
+	model.Hasher = model_reflect.HasherFunc(func([]byte, int) []byte { panic("broken") })
+	if _, err := model.SafeHash(); !errors.Is(err, model_reflect.ErrInvalidHasher) {
+		t.Errorf("TestModelReflectSafeHash: panic [%v]", err)
+	}
+}
+
+// countingHasher counts its Sum calls.
+type countingHasher struct {
+	calls *int
+}
+
+func (h countingHasher) Sum(model []byte, size int) []byte {
+	*h.calls++
+	return model_reflect.SHA256.Sum(model, size)
+}
+
+func TestModelReflectHashMemo(t *testing.T) {
+	calls := 0
+	model, _ := model_reflect.New(point{}, model_reflect.WithHasher(countingHasher{&calls}))
+	first := model.Hash()
+	clone := model
+	if clone.Hash() != first || model.Hash() != first || calls != 1 {
+		t.Errorf("TestModelReflectHashMemo: %d calls", calls)
+	}
+	clone.Hasher = model_reflect.DefaultHasher
+	if clone.Hash() == first || model.Hash() != first || calls != 2 {
+		t.Errorf("TestModelReflectHashMemo: assigned hasher, %d calls", calls)
+	}
+	model.SetHasher(model_reflect.SHA256)
+	if model.Hash() != first || clone.Hash() == first {
+		t.Errorf("TestModelReflectHashMemo: set hasher")
+	}
+}
@@ -0,0 +1,114 @@
+package model_reflect_test
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestModelTree(t *testing.T) {
+	info, _ := model_reflect.New((*testStruct2)(nil))
+	root := info.Model()
+	if root.Kind != model_reflect.KindStruct || root.String() != info.String() {
+		t.Fatalf("root: %v %s", root.Kind, root)
+	}
+	var ok *model_reflect.Model
+	for _, f := range root.Fields {
+		if f.Name == "Ok" {
+			ok = f
+		}
+	}
+	if ok == nil || ok.Kind != model_reflect.KindMap || !ok.Nullable ||
+		ok.Key.Kind != model_reflect.KindStruct || !ok.Elem.Fields[0].Embedded || ok.Elem.Fields[1].Name != "Data" {
+		t.Fatalf("Ok field: %+v", ok)
+	}
+	if rebuilt := model_reflect.FromModel(root); rebuilt.Hash() != info.Hash() {
+		t.Error("hash of rebuilt model differs")
+	}
+}
+
+func TestModelPretty(t *testing.T) {
+	model, err := model_reflect.New(segment{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  From: {
+    X: int,
+    Y: int
+  },
+  Path: []{
+    X: int,
+    Y: int
+  },
+  To: {
+    X: int,
+    Y: int
+  }
+}`
+	if got := model.Pretty(); got != want {
+		t.Errorf("pretty:\n%s", got)
+	}
+}
+
+func TestModelWriteTo(t *testing.T) {
+	model, _ := model_reflect.New((*testStruct2)(nil))
+	var b strings.Builder
+	n, err := model.WriteTo(&b)
+	if err != nil || b.String() != model.String() || n != int64(b.Len()) {
+		t.Errorf("write to: %d %s [%v]", n, b.String(), err)
+	}
+	b.Reset()
+	if _, err := (model_reflect.ModelInfo{}).WriteTo(&b); err != nil || b.String() != "" {
+		t.Errorf("empty: %q [%v]", b.String(), err)
+	}
+	b.Reset()
+	wide := wideModel(3)
+	if n, err := wide.WriteTo(&b); err != nil || b.String() != wide.String() || n != int64(b.Len()) ||
+		!strings.Contains(b.String(), "Hash:[32]uint8") {
+		t.Errorf("wide: %d %s [%v]", n, b.String(), err)
+	}
+	if array, _ := model_reflect.New([1000]int{}); array.String() != "[1000]int" {
+		t.Errorf("array: %s", array)
+	}
+}
+
+// wideModel returns the model of a struct with n fields, each a struct of
+// scalars, slices, maps and arrays.
+func wideModel(n int) *model_reflect.Model {
+	inner := reflect.TypeOf(struct {
+		ID    int
+		Name  string
+		Tags  []string
+		Attrs map[string]float64
+		Hash  [32]byte
+	}{})
+	fields := make([]reflect.StructField, n)
+	for i := range fields {
+		fields[i] = reflect.StructField{Name: fmt.Sprintf("F%d", i), Type: inner}
+	}
+	info, _ := model_reflect.NewFromType(reflect.StructOf(fields))
+	return info.Model()
+}
+
+func BenchmarkModelString(b *testing.B) {
+	root := wideModel(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = root.String()
+	}
+}
+
+func BenchmarkModelWriteTo(b *testing.B) {
+	root := wideModel(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = root.WriteTo(io.Discard)
+	}
+}
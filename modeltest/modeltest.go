@@ -0,0 +1,90 @@
+// Package modeltest checks models against golden files, replacing
+// hard-coded hashes in tests with reviewable canonical strings.
+package modeltest
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+// Dir is the directory golden files are kept in.
+var Dir = "testdata"
+
+// UpdateEnv is the environment variable that, set to a true value, makes
+// Snapshot write golden files instead of comparing against them.
+const UpdateEnv = "MODELTEST_UPDATE"
+
+// updating reports whether UpdateEnv is set, or an -update flag defined by
+// the test binary itself. The package defines no flag of its own.
+func updating() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(UpdateEnv)); ok {
+		return true
+	}
+	f := flag.Lookup("update")
+	return f != nil && f.Value.String() == "true"
+}
+
+// Snapshot compares the model of v with the golden file named after the
+// test, <Dir>/<test name>.model, and reports the differences. Run the test
+// with MODELTEST_UPDATE=1, or with -update if the test binary defines that
+// flag, to write the golden file instead.
+func Snapshot(t testing.TB, v any, opts ...model_reflect.Option) {
+	t.Helper()
+	model, _ := model_reflect.New(v, opts...)
+	path := filepath.Join(Dir, strings.ReplaceAll(t.Name(), "/", "_")+".model")
+	if updating() {
+		if err := os.MkdirAll(Dir, 0o755); err != nil {
+			t.Fatalf("modeltest: %v", err)
+			return
+		}
+		if err := os.WriteFile(path, []byte(model.String()+"\n"), 0o644); err != nil {
+			t.Fatalf("modeltest: %v", err)
+		}
+		return
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("modeltest: missing golden file %s, run with %s=1 to create it", path, UpdateEnv)
+		return
+	}
+	if err != nil {
+		t.Fatalf("modeltest: %v", err)
+		return
+	}
+	golden := strings.TrimSuffix(string(data), "\n")
+	if golden == model.String() {
+		return
+	}
+	tree, err := model_reflect.Parse(golden)
+	if err != nil {
+		t.Fatalf("modeltest: %s: %v", path, err)
+		return
+	}
+	t.Errorf("modeltest: model differs from %s:\n%s", path, describe(model_reflect.FromModel(tree).Diff(model)))
+}
+
+func describe(d model_reflect.ModelDiff) string {
+	b := &strings.Builder{}
+	for _, c := range d.Changes {
+		switch c.Kind {
+		case model_reflect.FieldAdded:
+			fmt.Fprintf(b, "  + %s: %s\n", c.Path, c.New)
+		case model_reflect.FieldRemoved:
+			fmt.Fprintf(b, "  - %s: %s\n", c.Path, c.Old)
+		case model_reflect.FieldRenamed:
+			fmt.Fprintf(b, "  ~ %s -> %s\n", c.OldPath, c.Path)
+		default:
+			fmt.Fprintf(b, "  ~ %s: %s -> %s\n", c.Path, c.Old, c.New)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package modeltest_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-modern/model_reflect/modeltest"
+)
+
+type (
+	user struct {
+		Name  string
+		Email string
+	}
+	userV2 struct {
+		Name string
+		Age  int
+	}
+)
+
+// recorder captures failures instead of failing the test.
+type recorder struct {
+	*testing.T
+	failures []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+// update is defined by the test binary, as users of the package may do.
+var update = flag.Bool("update", false, "update model golden files")
+
+func TestSnapshot(t *testing.T) {
+	modeltest.Snapshot(t, user{})
+}
+
+// skipUpdate skips tests whose golden files must not be rewritten.
+func skipUpdate(t *testing.T) {
+	if ok, _ := strconv.ParseBool(os.Getenv(modeltest.UpdateEnv)); ok || *update {
+		t.Skip("golden file is a fixture")
+	}
+}
+
+func TestSnapshotMismatch(t *testing.T) {
+	skipUpdate(t)
+	r := &recorder{T: t}
+	modeltest.Snapshot(r, userV2{})
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], "+ Age: int") ||
+		!strings.Contains(r.failures[0], "- Email: string") {
+		t.Errorf("failures: %q", r.failures)
+	}
+}
+
+func TestSnapshotMissing(t *testing.T) {
+	skipUpdate(t)
+	r := &recorder{T: t}
+	modeltest.Snapshot(r, user{})
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], modeltest.UpdateEnv+"=1") {
+		t.Errorf("failures: %q", r.failures)
+	}
+}
+
+func TestSnapshotUpdate(t *testing.T) {
+	dir := modeltest.Dir
+	modeltest.Dir = t.TempDir()
+	defer func() { modeltest.Dir = dir }()
+	t.Run("env", func(t *testing.T) {
+		t.Setenv(modeltest.UpdateEnv, "1")
+		modeltest.Snapshot(t, user{})
+		os.Unsetenv(modeltest.UpdateEnv)
+		data, err := os.ReadFile(filepath.Join(modeltest.Dir, "TestSnapshotUpdate_env.model"))
+		if err != nil || string(data) != "{ Email:string, Name:string }\n" {
+			t.Errorf("golden: %q [%v]", data, err)
+		}
+		modeltest.Snapshot(t, user{})
+	})
+	t.Run("flag", func(t *testing.T) {
+		defer func(old bool) { *update = old }(*update)
+		*update = true
+		modeltest.Snapshot(t, userV2{})
+		*update = false
+		data, err := os.ReadFile(filepath.Join(modeltest.Dir, "TestSnapshotUpdate_flag.model"))
+		if err != nil || string(data) != "{ Age:int, Name:string }\n" {
+			t.Errorf("golden: %q [%v]", data, err)
+		}
+		modeltest.Snapshot(t, userV2{})
+	})
+}
@@ -0,0 +1,31 @@
+package model_reflect
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnnamed is returned when a named root type is required.
+var ErrUnnamed = errors.New("unnamed model type")
+
+// OpenAPISchemas returns the OpenAPI 3.1 components.schemas object for the
+// given models. Every named struct type becomes a component referenced via
+// $ref, and the component of each model carries its canonical string and
+// hash, as by HashHex, as x-model and x-model-hash extensions.
+func OpenAPISchemas(models ...ModelInfo) ([]byte, error) {
+	s := newSchemaBuilder("#/components/schemas/", true)
+	for _, m := range models {
+		if m.root == nil || m.root.Kind == KindNil {
+			return nil, ErrNoType
+		}
+		if m.root.Kind != KindStruct || m.root.namedType() == "" {
+			return nil, fmt.Errorf("%w %s", ErrUnnamed, m)
+		}
+		s.count(m.root)
+		s.ref(m.root)
+		def := s.defs[s.names[m.root.typeKey()]].(map[string]any)
+		def["x-model"] = m.String()
+		def["x-model-hash"] = m.HashHex()
+	}
+	return marshalIndent(s.defs)
+}
@@ -0,0 +1,46 @@
+package model_reflect_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type apiAddress struct {
+	Street string `json:"street"`
+}
+
+type apiUser struct {
+	Name    string      `json:"name"`
+	Home    apiAddress  `json:"home"`
+	Work    *apiAddress `json:"work"`
+	Friends []apiUser   `json:"friends"`
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	user, _ := model_reflect.New(apiUser{})
+	b, err := model_reflect.OpenAPISchemas(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%s", b)
+	schemas := map[string]map[string]any{}
+	if err := json.Unmarshal(b, &schemas); err != nil {
+		t.Fatal(err)
+	}
+	if len(schemas) != 2 || schemas["apiAddress"] == nil {
+		t.Errorf("schemas: %v", schemas)
+	}
+	if schemas["apiUser"]["x-model"] != user.String() {
+		t.Errorf("x-model: %v", schemas["apiUser"]["x-model"])
+	}
+	if schemas["apiUser"]["x-model-hash"] != user.HashHex() {
+		t.Errorf("x-model-hash: %v", schemas["apiUser"]["x-model-hash"])
+	}
+	scalar, _ := model_reflect.New(0)
+	if _, err := model_reflect.OpenAPISchemas(scalar); !errors.Is(err, model_reflect.ErrUnnamed) {
+		t.Errorf("scalar: %v", err)
+	}
+}
@@ -0,0 +1,450 @@
+package model_reflect
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"golang.org/x/exp/slices"
+)
+
+// Option configures a single call to New.
+type Option func(*config)
+
+// EmbedMode selects how embedded structs are represented.
+type EmbedMode uint8
+
+const (
+	// EmbedFlatten promotes the fields of embedded structs, as encoding/json
+	// does. It is the default.
+	EmbedFlatten EmbedMode = iota
+	// EmbedNest represents embedded structs as a field named after the type,
+	// as msgpack without inline or mapstructure without squash do.
+	EmbedNest
+	// EmbedSkip leaves embedded structs out of the model.
+	EmbedSkip
+)
+
+type config struct {
+	nameTags         []string
+	interfaces       []reflect.Type
+	hashTags         []string
+	hasher           Hasher
+	typeArgs         bool
+	impls            map[reflect.Type][]reflect.Type
+	wellKnown        map[reflect.Type]string
+	types            map[reflect.Type]string
+	inline           bool
+	optionality      bool
+	ignoreMarkers    bool
+	declarationOrder bool
+	embedding        EmbedMode
+	maxDepth         int
+	cycleRefs        bool
+	typeRefs         bool
+	typeNames        bool
+	format           int
+	severities       []severityRule
+	strict           bool
+	funcs            bool
+	chans            bool
+	pointers         PointerPolicy
+	receivers        bool
+	methods          bool
+	messages         []reflect.Type
+	anchors          bool
+	byteStrings      bool
+	omitArrayLengths bool
+	definedNames     bool
+	fieldFilter      func(reflect.StructField, []string) bool
+	typeTransform    func(reflect.Type) (string, bool)
+	extensions       []Extension
+	sqlNulls         bool
+	durations        DurationForm
+	values           bool
+	// value is the value interfaces are resolved from with values set.
+	value reflect.Value
+	// ctx is set by NewContext for a single reflection and never cached.
+	ctx context.Context
+}
+
+// Config is an immutable, reusable set of options. Unlike the package-level
+// defaults it is safe to share between goroutines.
+type Config struct {
+	c *config
+}
+
+// NewConfig returns a Config built from a snapshot of the package defaults
+// with opts applied on top of it.
+func NewConfig(opts ...Option) Config {
+	return Config{c: newConfig(opts...)}
+}
+
+// With returns a copy of the Config with opts applied on top of it.
+func (c Config) With(opts ...Option) Config {
+	cfg := c.config()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return Config{c: cfg}
+}
+
+// New is a shorthand for New(v, WithConfig(c)).
+func (c Config) New(v any) (ModelInfo, error) {
+	return New(v, WithConfig(c))
+}
+
+// NewFromType is a shorthand for NewFromType(t, WithConfig(c)).
+func (c Config) NewFromType(t reflect.Type) (ModelInfo, error) {
+	return NewFromType(t, WithConfig(c))
+}
+
+// NewFromValue is a shorthand for NewFromValue(v, WithConfig(c)).
+func (c Config) NewFromValue(v reflect.Value) (ModelInfo, error) {
+	return NewFromValue(v, WithConfig(c))
+}
+
+// config returns a private copy of the configuration.
+func (c Config) config() *config {
+	if c.c == nil {
+		return newConfig()
+	}
+	return c.c.clone()
+}
+
+// WithConfig replaces all options set so far, including the package
+// defaults, with the given Config.
+func WithConfig(cfg Config) Option {
+	return func(c *config) {
+		*c = *cfg.config()
+	}
+}
+
+func (c *config) clone() *config {
+	clone := *c
+	clone.nameTags = slices.Clone(c.nameTags)
+	clone.interfaces = slices.Clone(c.interfaces)
+	clone.hashTags = slices.Clone(c.hashTags)
+	clone.messages = slices.Clone(c.messages)
+	clone.impls = cloneImplementations(c.impls)
+	clone.extensions = slices.Clone(c.extensions)
+	return &clone
+}
+
+// newConfig returns a config initialised from the package defaults with
+// opts applied on top of it.
+func newConfig(opts ...Option) *config {
+	c := &config{
+		nameTags:      slices.Clone(DefaultNameTags),
+		interfaces:    slices.Clone(DefaultInterfaces),
+		hasher:        DefaultHasher,
+		impls:         registeredImplementations(),
+		types:         registeredTypeNames(),
+		ignoreMarkers: true,
+		severities:    []severityRule{{ErrSkippedField, SeverityWarning}},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithNameTags sets the ordered list of struct tags consulted for field names.
+func WithNameTags(tags ...string) Option {
+	return func(c *config) {
+		c.nameTags = slices.Clone(tags)
+	}
+}
+
+// WithPreferredNameTags moves tags to the front of the tag list, keeping the
+// remaining tags as fallbacks in their current order.
+func WithPreferredNameTags(tags ...string) Option {
+	return func(c *config) {
+		rest := slices.DeleteFunc(c.nameTags, func(tag string) bool {
+			return slices.Contains(tags, tag)
+		})
+		c.nameTags = append(slices.Clone(tags), rest...)
+	}
+}
+
+// WithInline flattens struct fields whose name tag carries the inline or
+// squash option, such as yaml:",inline", bson:",inline" or
+// mapstructure:",squash", the same way embedded structs are flattened.
+// The first name tag present on a field decides.
+func WithInline() Option {
+	return func(c *config) {
+		c.inline = true
+	}
+}
+
+// WithIgnoreMarkers sets whether fields whose name tag is "-", such as
+// json:"-", are left out of the model as the encoders do. It is enabled by
+// default; disabling it keeps them under the name "-".
+func WithIgnoreMarkers(enabled bool) Option {
+	return func(c *config) {
+		c.ignoreMarkers = enabled
+	}
+}
+
+// WithOptionality marks pointer fields and fields tagged omitempty or
+// omitzero as optional (Stuff?:int), so changing whether a field may be
+// omitted changes the hash.
+func WithOptionality() Option {
+	return func(c *config) {
+		c.optionality = true
+	}
+}
+
+// WithTags includes the given struct tags verbatim in the canonical form
+// (Stuff:int`validate:"min=1"`), so changing them changes the hash.
+func WithTags(tags ...string) Option {
+	return func(c *config) {
+		c.hashTags = slices.Clone(tags)
+	}
+}
+
+// WithDeclarationOrder keeps struct fields in declaration order instead of
+// sorting them by name, for positional encodings where reordering fields is
+// a breaking change. Promoted fields take the place of their embedded struct.
+func WithDeclarationOrder() Option {
+	return func(c *config) {
+		c.declarationOrder = true
+	}
+}
+
+// WithEmbedding selects how embedded structs are represented. Fields marked
+// inline or squash are still flattened when WithInline is used.
+func WithEmbedding(mode EmbedMode) Option {
+	return func(c *config) {
+		c.embedding = mode
+	}
+}
+
+// WithMaxDepth limits the model to n levels of nested types. Deeper types
+// are rendered as <max-depth> and reported as a PathError wrapping
+// ErrMaxDepth. Zero means no limit.
+func WithMaxDepth(n int) Option {
+	return func(c *config) {
+		c.maxDepth = n
+	}
+}
+
+// WithCycleRefs renders recursive references as @TypeName instead of <...>
+// and does not report them as ErrLoopDetected, giving recursive models a
+// stable, error-free canonical form. Embedded structs that recurse into an
+// enclosing embedded struct are left out, as encoding/json does.
+func WithCycleRefs() Option {
+	return func(c *config) {
+		c.cycleRefs = true
+	}
+}
+
+// WithAnchors marks every type that recursive references lead back to with
+// a numbered anchor and renders the references by number, as in
+// &1{ Children:[]*1, Value:int }, so that recursive models differing inside
+// the cycle never share a canonical form. Like WithCycleRefs, recursion is
+// not reported as ErrLoopDetected.
+func WithAnchors() Option {
+	return func(c *config) {
+		c.anchors = true
+		c.cycleRefs = true
+	}
+}
+
+// WithByteStrings renders byte arrays such as [16]byte as []uint8, for codecs
+// that encode both as byte strings, so that switching between them does not
+// change the hash.
+func WithByteStrings() Option {
+	return func(c *config) {
+		c.byteStrings = true
+	}
+}
+
+// WithArrayLengths sets whether array lengths are part of the model. When
+// disabled, arrays render as slices ([N]T as []T), for encodings that treat
+// both alike. Lengths are included by default.
+func WithArrayLengths(enabled bool) Option {
+	return func(c *config) {
+		c.omitArrayLengths = !enabled
+	}
+}
+
+// WithDefinedNames renders defined non-struct types by name in front of
+// their underlying type, as in BigInt(int), so that replacing a semantic
+// type with its underlying type changes the hash.
+func WithDefinedNames() Option {
+	return func(c *config) {
+		c.definedNames = true
+	}
+}
+
+// WithFieldFilter leaves out every struct field for which keep returns
+// false, for example fields tagged internal:"true". Path holds the names of
+// the enclosing fields followed by the field's own name, as in Walk.
+// Models reflected with a filter are not cached.
+func WithFieldFilter(keep func(f reflect.StructField, path []string) bool) Option {
+	return func(c *config) {
+		c.fieldFilter = keep
+	}
+}
+
+// WithTypeTransform lets transform substitute the representation of whole
+// families of types, such as all generated enums, in one place. It is
+// called with every type, pointers removed, after the types set with
+// WithTypes and before any other handling; types for which it returns ok
+// are rendered as repr. Models reflected with a transform are not cached.
+func WithTypeTransform(transform func(t reflect.Type) (repr string, ok bool)) Option {
+	return func(c *config) {
+		c.typeTransform = transform
+	}
+}
+
+// WithExtensions registers extensions that describe the types they claim.
+// They are consulted in registration order after the types set with
+// WithTypes and WithTypeTransform; the first to claim a type wins. Options
+// of several calls add up.
+func WithExtensions(exts ...Extension) Option {
+	return func(c *config) {
+		c.extensions = append(c.extensions, exts...)
+	}
+}
+
+// WithSQLNulls renders the database/sql Null types as nullable values such
+// as ?string instead of as the structs they are, as in
+// { String:string, Valid:bool }, which is kept by default for code that
+// serializes them literally. WithSQLValuers takes precedence over both.
+func WithSQLNulls() Option {
+	return func(c *config) {
+		c.sqlNulls = true
+	}
+}
+
+// WithTypeRefs defines every named struct type used more than once by name
+// at its first occurrence, as in testB{ B:int }, and renders later uses as
+// @testB instead of expanding the body again. It implies WithCycleRefs.
+func WithTypeRefs() Option {
+	return func(c *config) {
+		c.typeRefs = true
+		c.cycleRefs = true
+	}
+}
+
+// WithFuncs renders func-typed values by their signature, as in
+// func(int, ...string) (bool, error), instead of leaving them out.
+// Interfaces in signatures that are not otherwise representable are
+// rendered by their type name.
+func WithFuncs() Option {
+	return func(c *config) {
+		c.funcs = true
+	}
+}
+
+// WithChans renders channels by their direction and element type, as in
+// chan<- { ID:int }, instead of leaving them out.
+func WithChans() Option {
+	return func(c *config) {
+		c.chans = true
+	}
+}
+
+// PointerPolicy selects how uintptr and unsafe.Pointer values are modeled.
+type PointerPolicy uint8
+
+const (
+	// PointerLegacy renders uintptr as a scalar and leaves unsafe.Pointer
+	// out, as earlier versions did.
+	PointerLegacy PointerPolicy = iota
+	// PointerSkip leaves both out of the model.
+	PointerSkip
+	// PointerPlaceholder renders both as <uintptr> or <unsafe.Pointer>.
+	PointerPlaceholder
+	// PointerError renders placeholders and reports every occurrence as
+	// ErrPointerField.
+	PointerError
+)
+
+// ErrPointerField is reported for uintptr and unsafe.Pointer values under
+// PointerError.
+var ErrPointerField = errors.New("pointer value")
+
+// DurationForm selects how time.Duration values are modeled, matching the
+// way the codec in use writes them.
+type DurationForm uint8
+
+const (
+	// DurationDefault renders durations as int64, or as duration with
+	// WithWellKnownTypes.
+	DurationDefault DurationForm = iota
+	// DurationInt64 renders durations as int64, as encoding/json writes
+	// them.
+	DurationInt64
+	// DurationName renders durations as duration.
+	DurationName
+	// DurationString renders durations as string, for codecs writing
+	// values such as "1m30s".
+	DurationString
+)
+
+// WithDurationForm sets how time.Duration values are modeled.
+func WithDurationForm(f DurationForm) Option {
+	return func(c *config) {
+		c.durations = f
+	}
+}
+
+// WithPointerPolicy sets how uintptr and unsafe.Pointer values are modeled.
+func WithPointerPolicy(p PointerPolicy) Option {
+	return func(c *config) {
+		c.pointers = p
+	}
+}
+
+// WithInterfaces sets the list of interfaces that make a type opaque.
+func WithInterfaces(ifaces ...reflect.Type) Option {
+	return func(c *config) {
+		c.interfaces = slices.Clone(ifaces)
+	}
+}
+
+// WithReceivers marks interfaces implemented only on the pointer receiver,
+// as in <*encoding.TextMarshaler>, since codecs treat values and pointers
+// of such types differently.
+func WithReceivers() Option {
+	return func(c *config) {
+		c.receivers = true
+	}
+}
+
+// WithMethods appends the exported method set of the root type, including
+// methods on the pointer receiver, to the canonical form, as in
+// { X:int } methods { Len:func() int }.
+func WithMethods() Option {
+	return func(c *config) {
+		c.methods = true
+	}
+}
+
+// WithHasher sets the hasher of the resulting ModelInfo.
+func WithHasher(h Hasher) Option {
+	return func(c *config) {
+		c.hasher = h
+	}
+}
+
+// WithTypeNames renders every named type with its package path and name,
+// as in example.com/pkg.Point{ X:int, Y:int }, so that structurally
+// identical types from different packages hash differently.
+func WithTypeNames() Option {
+	return func(c *config) {
+		c.typeNames = true
+	}
+}
+
+// WithTypeArgs renders instantiated generic types with their name and type
+// arguments (Box[int]{ V:int }), so distinct instantiations never share a
+// canonical form.
+func WithTypeArgs() Option {
+	return func(c *config) {
+		c.typeArgs = true
+	}
+}
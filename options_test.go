@@ -0,0 +1,620 @@
+package model_reflect_test
+
+import (
+	"encoding"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type taggedStruct struct {
+	Value int `json:"jsonName" cbor:"cborName"`
+}
+
+func TestWithNameTags(t *testing.T) {
+	model, err := model_reflect.New(taggedStruct{})
+	if err != nil || model.String() != "{ JsonName:int }" {
+		t.Errorf("default: %s [%v]", model, err)
+	}
+	model, err = model_reflect.New(taggedStruct{}, model_reflect.WithNameTags("cbor"))
+	if err != nil || model.String() != "{ CborName:int }" {
+		t.Errorf("cbor: %s [%v]", model, err)
+	}
+	model, err = model_reflect.New(taggedStruct{}, model_reflect.WithNameTags())
+	if err != nil || model.String() != "{ Value:int }" {
+		t.Errorf("no tags: %s [%v]", model, err)
+	}
+}
+
+func TestWithHasher(t *testing.T) {
+	model, _ := model_reflect.New(taggedStruct{})
+	salted, _ := model_reflect.New(taggedStruct{}, model_reflect.WithHasher(model_reflect.HashInfo{
+		Salt: []byte("salt"), Time: 1, Memory: 8, Threads: 1,
+	}))
+	if model.Hash() == salted.Hash() {
+		t.Error("salt did not change the hash")
+	}
+}
+
+func TestWithHasherFunc(t *testing.T) {
+	model, _ := model_reflect.New(taggedStruct{}, model_reflect.WithHasher(model_reflect.SHA256))
+	if model.Hash() != 0x0327a7d70cdff2a7 {
+		t.Errorf("sha256: %x", model.Hash())
+	}
+}
+
+func TestConfig(t *testing.T) {
+	cbor := model_reflect.NewConfig(model_reflect.WithNameTags("cbor"))
+	plain := cbor.With(model_reflect.WithNameTags())
+	if model, _ := cbor.New(taggedStruct{}); model.String() != "{ CborName:int }" {
+		t.Errorf("cbor: %s", model)
+	}
+	if model, _ := plain.New(taggedStruct{}); model.String() != "{ Value:int }" {
+		t.Errorf("plain: %s", model)
+	}
+	model, _ := model_reflect.New(taggedStruct{}, model_reflect.WithNameTags(), model_reflect.WithConfig(cbor))
+	if model.String() != "{ CborName:int }" {
+		t.Errorf("WithConfig: %s", model)
+	}
+	if model, _ := (model_reflect.Config{}).New(taggedStruct{}); model.String() != "{ JsonName:int }" {
+		t.Errorf("zero Config: %s", model)
+	}
+}
+
+type box[T any] struct {
+	Value T
+	Count int
+}
+
+type boxes struct {
+	Ints    box[int]
+	Strings *box[string]
+}
+
+func TestWithTypeArgs(t *testing.T) {
+	model, _ := model_reflect.New(boxes{}, model_reflect.WithTypeArgs())
+	want := "{ Ints:box[int]{ Count:int, Value:int }, Strings:box[string]{ Count:int, Value:string } }"
+	if model.String() != want {
+		t.Errorf("type args: %s", model)
+	}
+	a, _ := model_reflect.New(box[int]{}, model_reflect.WithTypeArgs())
+	b, _ := model_reflect.New(struct{ Count, Value int }{}, model_reflect.WithTypeArgs())
+	if a.String() == b.String() {
+		t.Errorf("instantiation and plain struct share %s", a)
+	}
+	if model, _ := model_reflect.New(boxes{}); model.String() != "{ Ints:{ Count:int, Value:int }, Strings:{ Count:int, Value:string } }" {
+		t.Errorf("default: %s", model)
+	}
+}
+
+type mongoStruct struct {
+	ID   string `json:"id" bson:"_id"`
+	Name string `json:"name"`
+}
+
+func TestWithPreferredNameTags(t *testing.T) {
+	model, _ := model_reflect.New(mongoStruct{}, model_reflect.WithPreferredNameTags("bson"))
+	if model.String() != "{ Name:string, _id:string }" {
+		t.Errorf("bson first: %s", model)
+	}
+	model, _ = model_reflect.New(mongoStruct{}, model_reflect.WithPreferredNameTags("bson", "json"))
+	if model.String() != "{ Name:string, _id:string }" {
+		t.Errorf("bson, json: %s", model)
+	}
+}
+
+type codecBase struct {
+	Version int `yaml:"version" toml:"version" bson:"v" mapstructure:"ver"`
+}
+
+type codecStruct struct {
+	Base    codecBase `yaml:",inline" toml:"base" bson:",inline" mapstructure:",squash"`
+	Title   string    `yaml:"title,omitempty" toml:"title,omitempty" bson:"t,omitempty" mapstructure:"name"`
+	Ignored string    `yaml:",omitempty"`
+}
+
+func TestCodecTags(t *testing.T) {
+	tests := map[string]string{
+		"yaml":         "{ Ignored:string, Title:string, Version:int }",
+		"toml":         "{ Base:{ Version:int }, Ignored:string, Title:string }",
+		"bson":         "{ Ignored:string, T:string, V:int }",
+		"mapstructure": "{ Ignored:string, Name:string, Ver:int }",
+	}
+	for tag, want := range tests {
+		model, _ := model_reflect.New(codecStruct{}, model_reflect.WithNameTags(tag), model_reflect.WithInline())
+		if model.String() != want {
+			t.Errorf("%s: %s", tag, model)
+		}
+	}
+	model, _ := model_reflect.New(codecStruct{}, model_reflect.WithNameTags("yaml"))
+	if model.String() != "{ Base:{ Version:int }, Ignored:string, Title:string }" {
+		t.Errorf("yaml without inline: %s", model)
+	}
+}
+
+type ignoredStruct struct {
+	Visible  int
+	Secret   string `json:"-"`
+	Internal string `json:",omitempty" msgpack:"-"`
+	Dash     string `json:"-,"`
+}
+
+func TestWithIgnoreMarkers(t *testing.T) {
+	model, err := model_reflect.New(ignoredStruct{})
+	if err != nil || model.String() != "{ -:string, Visible:int }" {
+		t.Errorf("default: %s [%v]", model, err)
+	}
+	model, err = model_reflect.New(ignoredStruct{}, model_reflect.WithIgnoreMarkers(false))
+	if err == nil || model.String() != "{ Visible:int }" {
+		t.Errorf("disabled: %s [%v]", model, err)
+	}
+}
+
+func TestWithOptionality(t *testing.T) {
+	model, _ := model_reflect.New((*TestStruct)(nil), model_reflect.WithOptionality())
+	want := "{ int, Data?:int, Lolipop:float32, Stuff?:int, Time:<encoding.BinaryMarshaler,encoding.BinaryUnmarshaler,encoding.TextMarshaler,encoding.TextUnmarshaler> }"
+	if model.String() != want {
+		t.Errorf("optionality: %s", model)
+	}
+	model, _ = model_reflect.New(struct{ P *int }{}, model_reflect.WithOptionality())
+	if model.String() != "{ P?:int }" {
+		t.Errorf("pointer: %s", model)
+	}
+}
+
+type validatedStruct struct {
+	Age  int    `json:"age,omitempty" validate:"min=18"`
+	Name string `json:"name"`
+}
+
+func TestWithTags(t *testing.T) {
+	model, _ := model_reflect.New(validatedStruct{}, model_reflect.WithTags("validate", "json"))
+	want := "{ Age:int`validate:\"min=18\" json:\"age,omitempty\"`, Name:string`json:\"name\"` }"
+	if model.String() != want {
+		t.Errorf("tags: %s", model)
+	}
+	plain, _ := model_reflect.New(validatedStruct{})
+	if plain.String() != "{ Age:int, Name:string }" {
+		t.Errorf("default: %s", plain)
+	}
+}
+
+type csvRow struct {
+	Zeta  string
+	Alpha int
+	codecBase
+	Mid bool
+}
+
+func TestWithDeclarationOrder(t *testing.T) {
+	model, _ := model_reflect.New(csvRow{}, model_reflect.WithDeclarationOrder())
+	if model.String() != "{ Zeta:string, Alpha:int, Version:int, Mid:bool }" {
+		t.Errorf("declaration order: %s", model)
+	}
+	model, _ = model_reflect.New(csvRow{})
+	if model.String() != "{ Alpha:int, Mid:bool, Version:int, Zeta:string }" {
+		t.Errorf("sorted: %s", model)
+	}
+}
+
+type EmbedBase struct {
+	Version int `mapstructure:"ver"`
+}
+
+type embedOuter struct {
+	EmbedBase
+	Squashed EmbedBase `mapstructure:",squash"`
+	Name     string
+}
+
+func TestWithEmbedding(t *testing.T) {
+	tests := []struct {
+		opts []model_reflect.Option
+		want string
+	}{
+		{nil, "{ Name:string, Squashed:{ Version:int }, Version:int }"},
+		{[]model_reflect.Option{model_reflect.WithEmbedding(model_reflect.EmbedNest)},
+			"{ EmbedBase:{ Version:int }, Name:string, Squashed:{ Version:int } }"},
+		{[]model_reflect.Option{model_reflect.WithEmbedding(model_reflect.EmbedSkip)},
+			"{ Name:string, Squashed:{ Version:int } }"},
+		{[]model_reflect.Option{
+			model_reflect.WithNameTags("mapstructure"),
+			model_reflect.WithInline(),
+			model_reflect.WithEmbedding(model_reflect.EmbedNest),
+		}, "{ EmbedBase:{ Ver:int }, Name:string, Ver:int }"},
+	}
+	for _, test := range tests {
+		model, _ := model_reflect.New(embedOuter{}, test.opts...)
+		if model.String() != test.want {
+			t.Errorf("got %s, want %s", model, test.want)
+		}
+	}
+}
+
+type deepStruct struct {
+	Level1 struct {
+		Level2 struct {
+			Items []struct{ Level4 int }
+		}
+	}
+}
+
+func TestWithMaxDepth(t *testing.T) {
+	model, err := model_reflect.New(deepStruct{}, model_reflect.WithMaxDepth(3))
+	if model.String() != "{ Level1:{ Level2:{ Items:<max-depth> } } }" {
+		t.Errorf("max depth: %s", model)
+	}
+	var pathErr *model_reflect.PathError
+	if !errors.Is(err, model_reflect.ErrMaxDepth) || !errors.As(err, &pathErr) ||
+		pathErr.Path != "Level1.Level2.Items" {
+		t.Errorf("error: %v", err)
+	}
+	if _, err := model_reflect.New(deepStruct{}); err != nil {
+		t.Errorf("unlimited: %v", err)
+	}
+}
+
+type point struct {
+	X, Y int
+}
+
+type segment struct {
+	From, To point
+	Path     []point
+}
+
+func TestWithTypeRefs(t *testing.T) {
+	model, err := model_reflect.New(segment{}, model_reflect.WithTypeRefs())
+	if err != nil || model.String() != "{ From:point{ X:int, Y:int }, Path:[]@point, To:@point }" {
+		t.Errorf("type refs: %s [%v]", model, err)
+	}
+	model, err = model_reflect.New((*testA)(nil), model_reflect.WithTypeRefs())
+	if err != nil || model.String() != "{ A:int, B:int, X:testB{ A:int, B:int, X:@testB } }" {
+		t.Errorf("recursive: %s [%v]", model, err)
+	}
+	if model, _ := model_reflect.New(segment{}); model.String() != "{ From:{ X:int, Y:int }, Path:[]{ X:int, Y:int }, To:{ X:int, Y:int } }" {
+		t.Errorf("default: %s", model)
+	}
+}
+
+type otherPoint struct {
+	X, Y int
+}
+
+func TestWithTypeNames(t *testing.T) {
+	model, err := model_reflect.New(point{}, model_reflect.WithTypeNames())
+	if err != nil || model.String() != "github.com/go-modern/model_reflect_test.point{ X:int, Y:int }" {
+		t.Errorf("type names: %s [%v]", model, err)
+	}
+	other, _ := model_reflect.New(otherPoint{}, model_reflect.WithTypeNames())
+	if model.Hash() == other.Hash() {
+		t.Errorf("nominal hashes equal: %s", other)
+	}
+	model, _ = model_reflect.New(point{})
+	other, _ = model_reflect.New(otherPoint{})
+	if model.Hash() != other.Hash() {
+		t.Errorf("structural hashes differ")
+	}
+}
+
+type service struct {
+	Name  string
+	Get   func(id int) (*point, error)
+	Log   func(format string, args ...any)
+	Close func()
+}
+
+func TestWithFuncs(t *testing.T) {
+	if model, _ := model_reflect.New(service{}); model.String() != "{ Name:string }" {
+		t.Errorf("default: %s", model)
+	}
+	model, err := model_reflect.New(service{}, model_reflect.WithFuncs())
+	want := "{ Close:func(), Get:func(int) ({ X:int, Y:int }, error), Log:func(string, ...any), Name:string }"
+	if err != nil || model.String() != want {
+		t.Errorf("funcs: %s [%v]", model, err)
+	}
+	tree, err := model_reflect.Parse(model.String())
+	if err != nil || tree.String() != want {
+		t.Errorf("parse: %s [%v]", tree, err)
+	}
+}
+
+type pipeline struct {
+	In      <-chan point
+	Out     chan<- point
+	Control chan bool
+}
+
+func TestWithChans(t *testing.T) {
+	if model, _ := model_reflect.New(pipeline{}); model.String() != "{  }" {
+		t.Errorf("default: %s", model)
+	}
+	model, err := model_reflect.New(pipeline{}, model_reflect.WithChans())
+	want := "{ Control:chan bool, In:<-chan { X:int, Y:int }, Out:chan<- { X:int, Y:int } }"
+	if err != nil || model.String() != want {
+		t.Errorf("chans: %s [%v]", model, err)
+	}
+	tree, err := model_reflect.Parse(want)
+	if err != nil || tree.String() != want || tree.Fields[1].Dir != reflect.RecvDir {
+		t.Errorf("parse: %s [%v]", tree, err)
+	}
+}
+
+type handles struct {
+	Name string
+	Raw  unsafe.Pointer
+	Addr uintptr
+}
+
+func TestWithPointerPolicy(t *testing.T) {
+	for policy, want := range map[model_reflect.PointerPolicy]string{
+		model_reflect.PointerLegacy:      "{ Addr:uintptr, Name:string }",
+		model_reflect.PointerSkip:        "{ Name:string }",
+		model_reflect.PointerPlaceholder: "{ Addr:<uintptr>, Name:string, Raw:<unsafe.Pointer> }",
+		model_reflect.PointerError:       "{ Addr:<uintptr>, Name:string, Raw:<unsafe.Pointer> }",
+	} {
+		model, err := model_reflect.New(handles{}, model_reflect.WithPointerPolicy(policy))
+		if model.String() != want {
+			t.Errorf("policy %d: %s", policy, model)
+		}
+		if (policy == model_reflect.PointerError) != (err != nil) {
+			t.Errorf("policy %d: %v", policy, err)
+		}
+	}
+	_, err := model_reflect.New(handles{}, model_reflect.WithPointerPolicy(model_reflect.PointerError))
+	var pathErr *model_reflect.PathError
+	if !errors.Is(err, model_reflect.ErrPointerField) || !errors.As(err, &pathErr) || pathErr.Path != "Addr" {
+		t.Errorf("error: %v", err)
+	}
+}
+
+type (
+	valueText   struct{ V int }
+	pointerText struct{ V int }
+	receivers   struct {
+		Value   valueText
+		Pointer pointerText
+	}
+)
+
+func (valueText) MarshalText() ([]byte, error) { return nil, nil }
+
+func (*pointerText) MarshalText() ([]byte, error) { return nil, nil }
+
+func TestWithReceivers(t *testing.T) {
+	text := reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	model, _ := model_reflect.New(receivers{}, model_reflect.WithInterfaces(text))
+	if model.String() != "{ Pointer:<encoding.TextMarshaler>, Value:<encoding.TextMarshaler> }" {
+		t.Errorf("default: %s", model)
+	}
+	model, _ = model_reflect.New(receivers{}, model_reflect.WithInterfaces(text), model_reflect.WithReceivers())
+	if model.String() != "{ Pointer:<*encoding.TextMarshaler>, Value:<encoding.TextMarshaler> }" {
+		t.Errorf("receivers: %s", model)
+	}
+}
+
+type counter struct {
+	N int
+}
+
+func (c counter) Len() int { return c.N }
+
+func (c *counter) Add(delta int, labels ...string) error { return nil }
+
+func (c counter) private() {} //nolint:unused
+
+func TestWithMethods(t *testing.T) {
+	model, err := model_reflect.New(counter{}, model_reflect.WithMethods())
+	want := "{ N:int } methods { Add:func(int, ...string) error, Len:func() int }"
+	if err != nil || model.String() != want {
+		t.Errorf("methods: %s [%v]", model, err)
+	}
+	if plain, _ := model_reflect.New(counter{}); plain.String() != "{ N:int }" {
+		t.Errorf("default: %s", plain)
+	}
+	tree, err := model_reflect.Parse(want)
+	if err != nil || tree.String() != want || len(tree.Methods) != 2 {
+		t.Errorf("parse: %s [%v]", tree, err)
+	}
+}
+
+type fixedID struct {
+	ID   [16]byte
+	Sums [2]int
+}
+
+type sliceID struct {
+	ID   []byte
+	Sums [2]int
+}
+
+func TestWithByteStrings(t *testing.T) {
+	fixed, _ := model_reflect.New(fixedID{}, model_reflect.WithByteStrings())
+	slice, _ := model_reflect.New(sliceID{}, model_reflect.WithByteStrings())
+	if fixed.String() != "{ ID:[]uint8, Sums:[2]int }" || fixed.Hash() != slice.Hash() {
+		t.Errorf("byte strings: %s %s", fixed, slice)
+	}
+	if plain, _ := model_reflect.New(fixedID{}); plain.String() != "{ ID:[16]uint8, Sums:[2]int }" {
+		t.Errorf("default: %s", plain)
+	}
+}
+
+func TestWithArrayLengths(t *testing.T) {
+	model, _ := model_reflect.New(fixedID{}, model_reflect.WithArrayLengths(false))
+	if model.String() != "{ ID:[]uint8, Sums:[]int }" {
+		t.Errorf("array lengths: %s", model)
+	}
+	if model, _ := model_reflect.New(fixedID{}, model_reflect.WithArrayLengths(true)); model.String() != "{ ID:[16]uint8, Sums:[2]int }" {
+		t.Errorf("default: %s", model)
+	}
+}
+
+type (
+	bigInt  int
+	tagList []string
+
+	ledger struct {
+		Balance bigInt
+		Tags    tagList
+		Raw     int
+		Entries []bigInt
+	}
+)
+
+func TestWithDefinedNames(t *testing.T) {
+	model, err := model_reflect.New(ledger{}, model_reflect.WithDefinedNames())
+	want := "{ Balance:bigInt(int), Entries:[]bigInt(int), Raw:int, Tags:tagList([]string) }"
+	if err != nil || model.String() != want {
+		t.Errorf("defined names: %s [%v]", model, err)
+	}
+	if plain, _ := model_reflect.New(ledger{}); plain.String() != "{ Balance:int, Entries:[]int, Raw:int, Tags:[]string }" {
+		t.Errorf("default: %s", plain)
+	}
+	if tree, err := model_reflect.Parse(want); err != nil || tree.String() != want {
+		t.Errorf("parse: %s [%v]", tree, err)
+	}
+}
+
+type (
+	secret struct{ Value string }
+
+	reflectTags struct {
+		Account string  `json:"account" reflect:"name=ID"`
+		Secret  *secret `reflect:"opaque"`
+		Amount  bigInt  `reflect:"type=string"`
+		Legacy  int     `reflect:"uuid"`
+		Both    int     `reflect:"name=Count,type=uint64"`
+		Skipped int     `reflect:"-"`
+	}
+)
+
+func TestReflectTag(t *testing.T) {
+	model, err := model_reflect.New(reflectTags{})
+	want := "{ Amount:string, Count:uint64, ID:string, Legacy:uuid, Secret:<model_reflect_test.secret> }"
+	if err != nil || model.String() != want {
+		t.Errorf("reflect tag: %s [%v]", model, err)
+	}
+	if f, ok := model.Field("ID"); !ok || f.GoName != "Account" || f.Model.WireName != "ID" {
+		t.Errorf("reflect tag: name %+v", f)
+	}
+	if f, _ := model.Field("Secret"); !f.Model.Nullable {
+		t.Error("reflect tag: opaque pointer is not nullable")
+	}
+	if tree, err := model_reflect.Parse(want); err != nil || tree.String() != want {
+		t.Errorf("parse: %s [%v]", tree, err)
+	}
+}
+
+type (
+	auditInfo struct {
+		By   string
+		Note string `internal:"true"`
+	}
+
+	filtered struct {
+		auditInfo
+		ID     int
+		Secret string `internal:"true"`
+		Items  []struct {
+			SKU  string
+			Cost int `internal:"true"`
+		}
+	}
+)
+
+func TestWithFieldFilter(t *testing.T) {
+	paths := map[string]bool{}
+	keep := func(f reflect.StructField, path []string) bool {
+		paths[strings.Join(path, ".")] = true
+		return f.Tag.Get("internal") != "true"
+	}
+	model, err := model_reflect.New(filtered{}, model_reflect.WithFieldFilter(keep))
+	if err != nil || model.String() != "{ By:string, ID:int, Items:[]{ SKU:string } }" {
+		t.Errorf("field filter: %s [%v]", model, err)
+	}
+	for _, path := range []string{"auditInfo", "Note", "Secret", "Items.Cost"} {
+		if !paths[path] {
+			t.Errorf("field filter: path %s not seen in %v", path, paths)
+		}
+	}
+	drop := func(reflect.StructField, []string) bool { return false }
+	if model, _ := model_reflect.New(point{}, model_reflect.WithFieldFilter(drop)); model.String() != "{  }" {
+		t.Errorf("field filter: not cached %s", model)
+	}
+}
+
+type (
+	colorEnum  int32
+	statusEnum int32
+
+	enums struct {
+		Color  colorEnum
+		Status *statusEnum
+		Count  int32
+	}
+)
+
+func (colorEnum) EnumDescriptor() {}
+
+func (statusEnum) EnumDescriptor() {}
+
+func TestWithTypeTransform(t *testing.T) {
+	enum := reflect.TypeOf((*interface{ EnumDescriptor() })(nil)).Elem()
+	transform := func(t reflect.Type) (string, bool) {
+		if t.Implements(enum) {
+			return "enum:" + t.Name(), true
+		}
+		return "", false
+	}
+	model, err := model_reflect.New(enums{}, model_reflect.WithTypeTransform(transform))
+	if err != nil || model.String() != "{ Color:enum:colorEnum, Count:int32, Status:enum:statusEnum }" {
+		t.Errorf("type transform: %s [%v]", model, err)
+	}
+	if plain, _ := model_reflect.New(enums{}); plain.String() != "{ Color:int32, Count:int32, Status:int32 }" {
+		t.Errorf("default: %s", plain)
+	}
+}
+
+func TestWithSQLNulls(t *testing.T) {
+	model, err := model_reflect.New(sqlAccount{})
+	want := "{ ID:int64, Name:{ String:string, Valid:bool }, Score:{ Float64:float64, Valid:bool } }"
+	if err != nil || model.String() != want {
+		t.Errorf("default: %s [%v]", model, err)
+	}
+	model, err = model_reflect.New(sqlAccount{}, model_reflect.WithSQLNulls())
+	if err != nil || model.String() != "{ ID:int64, Name:?string, Score:?float64 }" {
+		t.Errorf("nulls: %s [%v]", model, err)
+	}
+	if f := model.Model().Fields[1]; f.Kind != model_reflect.KindNullable || !f.Nullable {
+		t.Errorf("node: %v %t", f.Kind, f.Nullable)
+	}
+}
+
+func TestWithDurationForm(t *testing.T) {
+	type timeout struct{ After time.Duration }
+	tests := []struct {
+		form model_reflect.DurationForm
+		want string
+	}{
+		{model_reflect.DurationDefault, "{ After:int64 }"},
+		{model_reflect.DurationInt64, "{ After:int64 }"},
+		{model_reflect.DurationName, "{ After:duration }"},
+		{model_reflect.DurationString, "{ After:string }"},
+	}
+	for _, test := range tests {
+		model, err := model_reflect.New(timeout{}, model_reflect.WithDurationForm(test.form))
+		if err != nil || model.String() != test.want {
+			t.Errorf("form %d: %s [%v]", test.form, model, err)
+		}
+	}
+	model, _ := model_reflect.New(timeout{}, model_reflect.WithWellKnownTypes(),
+		model_reflect.WithDurationForm(model_reflect.DurationInt64))
+	if model.String() != "{ After:int64 }" {
+		t.Errorf("well-known: %s", model)
+	}
+}
@@ -0,0 +1,396 @@
+package model_reflect
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrSyntax is returned by Parse for strings that are not canonical models.
+var ErrSyntax = errors.New("invalid model syntax")
+
+var scalarKinds = map[string]bool{}
+
+func init() {
+	for k := reflect.Bool; k <= reflect.UnsafePointer; k++ {
+		switch k {
+		case reflect.Array, reflect.Map, reflect.Slice, reflect.Struct, reflect.Pointer, reflect.Interface:
+		default:
+			scalarKinds[k.String()] = true
+		}
+	}
+}
+
+// Parse turns a canonical model string, as returned by String, back into a
+// model tree, so that stored models can be diffed against current code with
+// FromModel(tree).Diff. The tree renders to the same string and therefore
+// hashes identically.
+//
+// Only what the canonical form records is recovered: Type, GoName and
+// WireName are unset, Nullable is set on KindNullable nodes only, bare
+// words other than Go kind names become KindNamed, even extension
+// fragments without children, and back-references become KindRef. A
+// format version prefix is checked and skipped; use ModelInfo.UnmarshalText
+// to keep it.
+func Parse(s string) (*Model, error) {
+	version, body := splitFormat(s)
+	if version > FormatLatest {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrSyntax, version)
+	}
+	p := &parser{s: s, pos: len(s) - len(body)}
+	n, err := p.typ()
+	if err == nil && p.consume(" methods ") {
+		var methods *Model
+		if methods, err = p.structType(); err == nil {
+			n.Methods = methods.Fields
+		}
+	}
+	if err == nil && p.pos < len(s) {
+		err = p.errorf("unexpected %q", s[p.pos:])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w at offset %d: %s", ErrSyntax, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) peek(prefix string) bool {
+	return strings.HasPrefix(p.s[p.pos:], prefix)
+}
+
+func (p *parser) consume(prefix string) bool {
+	if !p.peek(prefix) {
+		return false
+	}
+	p.pos += len(prefix)
+	return true
+}
+
+func (p *parser) expect(prefix string) error {
+	if !p.consume(prefix) {
+		return p.errorf("expected %q", prefix)
+	}
+	return nil
+}
+
+func (p *parser) typ() (*Model, error) {
+	switch {
+	case p.consume("<-chan "):
+		return p.channel(reflect.RecvDir)
+	case p.consume("chan<- "):
+		return p.channel(reflect.SendDir)
+	case p.consume("chan "):
+		return p.channel(reflect.BothDir)
+	case p.consume("<nil>"):
+		return &Model{Kind: KindNil}, nil
+	case p.consume("<...>"):
+		return &Model{Kind: KindLoop}, nil
+	case p.consume("<?>"):
+		return &Model{Kind: KindUnknown}, nil
+	case p.consume("<max-depth>"):
+		return &Model{Kind: KindTruncated}, nil
+	case p.consume("<"):
+		end := strings.IndexByte(p.s[p.pos:], '>')
+		if end < 0 {
+			return nil, p.errorf("unterminated interface list")
+		}
+		n := &Model{Kind: KindOpaque, Repr: p.s[p.pos : p.pos+end]}
+		p.pos += end + 1
+		return n, nil
+	case p.consume("&"):
+		anchor, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		n, err := p.typ()
+		if err != nil {
+			return nil, err
+		}
+		n.Anchor = anchor
+		return n, nil
+	case p.consume("*"):
+		anchor, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		return &Model{Kind: KindLoop, Repr: "*" + strconv.Itoa(anchor)}, nil
+	case p.consume("?"):
+		elem, err := p.typ()
+		return &Model{Kind: KindNullable, Nullable: true, Elem: elem}, err
+	case p.consume("@"):
+		word := p.word()
+		if word == "" {
+			return nil, p.errorf("missing reference name")
+		}
+		return &Model{Kind: KindRef, Repr: "@" + word}, nil
+	case p.consume("[]"):
+		elem, err := p.typ()
+		return &Model{Kind: KindSlice, Elem: elem}, err
+	case p.consume("func("):
+		return p.function()
+	case p.consume("map["):
+		key, err := p.typ()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		elem, err := p.typ()
+		return &Model{Kind: KindMap, Key: key, Elem: elem}, err
+	case p.consume("["):
+		end := strings.IndexByte(p.s[p.pos:], ']')
+		if end < 0 {
+			return nil, p.errorf("unterminated array length")
+		}
+		length, err := strconv.Atoi(p.s[p.pos : p.pos+end])
+		if err != nil {
+			return nil, p.errorf("invalid array length")
+		}
+		p.pos += end + 1
+		elem, err := p.typ()
+		return &Model{Kind: KindArray, Len: length, Elem: elem}, err
+	case p.peek("{"):
+		return p.structType()
+	case p.consume("("):
+		return p.union()
+	}
+
+	word := p.word()
+	switch {
+	case word == "":
+		return nil, p.errorf("unexpected %q", p.s[p.pos:])
+	case p.peek("{"):
+		n, err := p.structType()
+		if err != nil {
+			return nil, err
+		}
+		n.TypeName = word
+		return n, nil
+	case p.consume("("):
+		n, err := p.typ()
+		if err != nil {
+			return nil, err
+		}
+		n.TypeName = word
+		return n, p.expect(")")
+	case p.consume("<"):
+		return p.custom(word)
+	case scalarKinds[word]:
+		return &Model{Kind: KindScalar, Repr: word}, nil
+	}
+	return &Model{Kind: KindNamed, Repr: word}, nil
+}
+
+// number reads an anchor number.
+func (p *parser) number() (int, error) {
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	n, err := strconv.Atoi(p.s[start:p.pos])
+	if err != nil || n < 1 {
+		return 0, p.errorf("invalid anchor")
+	}
+	return n, nil
+}
+
+// word reads a type or reference name including generic type arguments.
+func (p *parser) word() string {
+	start := p.pos
+	depth := 0
+	for p.pos < len(p.s) {
+		switch c := p.s[p.pos]; {
+		case c == '[':
+			if p.pos == start {
+				return ""
+			}
+			depth++
+		case c == ']':
+			if depth == 0 {
+				return p.s[start:p.pos]
+			}
+			depth--
+		case depth == 0 && strings.IndexByte(" ,:?{}()<>|`@", c) >= 0:
+			return p.s[start:p.pos]
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *parser) structType() (*Model, error) {
+	n := &Model{Kind: KindStruct}
+	if p.consume("{  }") {
+		return n, nil
+	}
+	if err := p.expect("{ "); err != nil {
+		return nil, err
+	}
+	for {
+		f, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		n.Fields = append(n.Fields, f)
+		if p.consume(" }") {
+			return n, nil
+		}
+		if err := p.expect(", "); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) field() (*Model, error) {
+	start := p.pos
+	end := strings.IndexAny(p.s[p.pos:], ":?, {}()[]<>|`")
+	name, optional, embedded := "", false, false
+	switch {
+	case end > 0 && p.consume(p.s[start:start+end]+"?:"):
+		name, optional = p.s[start:start+end], true
+	case end > 0 && p.consume(p.s[start:start+end]+":"):
+		name = p.s[start : start+end]
+	default:
+		embedded = true
+	}
+	f, err := p.typ()
+	if err != nil {
+		return nil, err
+	}
+	f.Name, f.Optional, f.Embedded = name, optional, embedded
+	if p.peek("`") {
+		start := p.pos
+		tag, err := p.annotation()
+		if err != nil {
+			return nil, err
+		}
+		f.Tag = reflect.StructTag(tag)
+		f.Annotation = p.s[start:p.pos]
+	}
+	return f, nil
+}
+
+// annotation reads a backquoted list of key:"value" pairs and returns its
+// content.
+func (p *parser) annotation() (string, error) {
+	p.pos++
+	start := p.pos
+	for {
+		colon := strings.IndexByte(p.s[p.pos:], ':')
+		if colon < 0 {
+			return "", p.errorf("unterminated annotation")
+		}
+		p.pos += colon + 1
+		value, err := strconv.QuotedPrefix(p.s[p.pos:])
+		if err != nil {
+			return "", p.errorf("invalid annotation value")
+		}
+		p.pos += len(value)
+		if p.consume("`") {
+			return p.s[start : p.pos-1], nil
+		}
+		if err := p.expect(" "); err != nil {
+			return "", err
+		}
+	}
+}
+
+func (p *parser) union() (*Model, error) {
+	n := &Model{Kind: KindUnion}
+	if p.consume(")") {
+		return n, nil
+	}
+	for {
+		v, err := p.typ()
+		if err != nil {
+			return nil, err
+		}
+		n.Variants = append(n.Variants, v)
+		if p.consume(")") {
+			return n, nil
+		}
+		if err := p.expect("|"); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) function() (*Model, error) {
+	n := &Model{Kind: KindFunc}
+	for !p.consume(")") {
+		if len(n.In) > 0 {
+			if err := p.expect(", "); err != nil {
+				return nil, err
+			}
+		}
+		variadic := p.consume("...")
+		in, err := p.typ()
+		if err != nil {
+			return nil, err
+		}
+		if variadic {
+			in = &Model{Kind: KindSlice, Elem: in}
+			n.Variadic = true
+		}
+		n.In = append(n.In, in)
+	}
+	switch {
+	case p.consume(" ("):
+		for {
+			out, err := p.typ()
+			if err != nil {
+				return nil, err
+			}
+			n.Out = append(n.Out, out)
+			if p.consume(")") {
+				return n, nil
+			}
+			if err := p.expect(", "); err != nil {
+				return nil, err
+			}
+		}
+	case p.peek(" ") && !p.peek(" }"):
+		p.pos++
+		out, err := p.typ()
+		if err != nil {
+			return nil, err
+		}
+		n.Out = append(n.Out, out)
+	}
+	return n, nil
+}
+
+// custom reads the child list of an extension fragment.
+func (p *parser) custom(repr string) (*Model, error) {
+	n := &Model{Kind: KindCustom, Repr: repr}
+	for {
+		a, err := p.typ()
+		if err != nil {
+			return nil, err
+		}
+		n.Args = append(n.Args, a)
+		if p.consume(">") {
+			return n, nil
+		}
+		if err := p.expect(", "); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) channel(dir reflect.ChanDir) (*Model, error) {
+	elem, err := p.typ()
+	return &Model{Kind: KindChan, Dir: dir, Elem: elem}, err
+}
@@ -0,0 +1,115 @@
+package model_reflect_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestParse(t *testing.T) {
+	models := []model_reflect.ModelInfo{}
+	for _, m := range []struct {
+		v    any
+		opts []model_reflect.Option
+	}{
+		{nil, nil},
+		{(*testStruct2)(nil), nil},
+		{(*testA)(nil), nil},
+		{(*testA)(nil), []model_reflect.Option{model_reflect.WithCycleRefs()}},
+		{anchorA{}, []model_reflect.Option{model_reflect.WithAnchors()}},
+		{segment{}, []model_reflect.Option{model_reflect.WithTypeRefs()}},
+		{boxes{}, []model_reflect.Option{model_reflect.WithTypeArgs()}},
+		{point{}, []model_reflect.Option{model_reflect.WithTypeNames()}},
+		{validatedStruct{}, []model_reflect.Option{model_reflect.WithTags("validate", "json")}},
+		{struct{ P *int }{}, []model_reflect.Option{model_reflect.WithOptionality()}},
+		{struct{ M map[string][4]bool }{}, nil},
+		{sqlAccount{}, []model_reflect.Option{model_reflect.WithSQLNulls()}},
+		{drawing{}, []model_reflect.Option{
+			model_reflect.WithImplementations(shapeType, (*square)(nil), circle{}),
+		}},
+	} {
+		model, _ := model_reflect.New(m.v, m.opts...)
+		models = append(models, model)
+	}
+	for _, model := range models {
+		tree, err := model_reflect.Parse(model.String())
+		if err != nil {
+			t.Errorf("%s: %v", model, err)
+			continue
+		}
+		if parsed := model_reflect.FromModel(tree); parsed.String() != model.String() || parsed.Hash() != model.Hash() {
+			t.Errorf("round trip: %s != %s", parsed, model)
+		}
+	}
+}
+
+func TestParseDiff(t *testing.T) {
+	v1, _ := model_reflect.New(orderV1{})
+	v2, _ := model_reflect.New(orderV2{})
+	tree, err := model_reflect.Parse(v1.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := model_reflect.FromModel(tree).Diff(v2)
+	if want := v1.Diff(v2); len(got.Changes) != len(want.Changes) {
+		t.Errorf("diff: %v, want %v", got, want)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, s := range []string{"", "{ A:int", "{ A:int }x", "[x]int", "map[string", "(int|"} {
+		if _, err := model_reflect.Parse(s); !errors.Is(err, model_reflect.ErrSyntax) {
+			t.Errorf("%q: %v", s, err)
+		}
+	}
+}
+
+type (
+	exportTree struct {
+		Label    string
+		Children []exportTree
+		Origin   exportPoint
+	}
+	exportPoint struct{ X, Y int }
+)
+
+func TestParseExport(t *testing.T) {
+	exporters := map[string]func(m model_reflect.ModelInfo) (any, error){
+		"JSONSchema": func(m model_reflect.ModelInfo) (any, error) { return m.JSONSchema() },
+		"OpenAPI":    func(m model_reflect.ModelInfo) (any, error) { return model_reflect.OpenAPISchemas(m) },
+		"Proto":      func(m model_reflect.ModelInfo) (any, error) { return m.Proto("x") },
+		"Avro":       func(m model_reflect.ModelInfo) (any, error) { return m.Avro("x") },
+		"CDDL":       func(m model_reflect.ModelInfo) (any, error) { return m.CDDL() },
+		"GraphQL":    func(m model_reflect.ModelInfo) (any, error) { return m.GraphQL() },
+		"CreateTable": func(m model_reflect.ModelInfo) (any, error) {
+			return m.CreateTable("x", model_reflect.Postgres)
+		},
+	}
+	named, _ := model_reflect.New(exportTree{}, model_reflect.WithTypeNames(), model_reflect.WithCycleRefs())
+	unnamed, _ := model_reflect.New(exportTree{})
+	for _, model := range []model_reflect.ModelInfo{named, unnamed} {
+		tree, err := model_reflect.Parse(model.String())
+		if err != nil {
+			t.Fatalf("%s: %v", model, err)
+		}
+		parsed := model_reflect.FromModel(tree)
+		for name, export := range exporters {
+			got, err := export(parsed)
+			if model.String() == unnamed.String() {
+				// Type names are lost, so only exporters that do not need
+				// them succeed.
+				unnamedOK := name == "JSONSchema" || name == "CDDL" || name == "CreateTable"
+				if unnamedOK && err != nil || !unnamedOK && !errors.Is(err, model_reflect.ErrUnnamed) {
+					t.Errorf("%s unnamed: %v [%v]", name, got, err)
+				}
+				continue
+			}
+			want, wantErr := export(model)
+			if fmt.Sprint(got) != fmt.Sprint(want) || (err == nil) != (wantErr == nil) {
+				t.Errorf("%s %s:\n%s [%v]\nwant:\n%s [%v]", name, model, got, err, want, wantErr)
+			}
+		}
+	}
+}
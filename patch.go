@@ -0,0 +1,87 @@
+package model_reflect
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type (
+	// patchOp is an RFC 6902 add or replace operation, which must have a
+	// value member even when the value is null.
+	patchOp struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}
+	// removeOp is an RFC 6902 remove operation, which has no value.
+	removeOp struct {
+		Op   string `json:"op"`
+		Path string `json:"path"`
+	}
+)
+
+// JSONPatch returns the RFC 6902 JSON Patch that turns the JSON Schema of m
+// into the JSON Schema of other, so that schema registries and migration
+// planners can consume the change. Arrays that differ are replaced whole.
+func (m ModelInfo) JSONPatch(other ModelInfo) ([]byte, error) {
+	from, err := schemaValue(m)
+	if err != nil {
+		return nil, err
+	}
+	to, err := schemaValue(other)
+	if err != nil {
+		return nil, err
+	}
+	ops := []any{}
+	jsonDiff("", from, to, &ops)
+	return marshalIndent(ops)
+}
+
+// schemaValue returns the JSON Schema of m decoded into generic values.
+func schemaValue(m ModelInfo) (any, error) {
+	doc, err := m.JSONSchema()
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	return v, json.Unmarshal(doc, &v)
+}
+
+func jsonDiff(path string, a, b any, ops *[]any) {
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+	if !aok || !bok {
+		if !reflect.DeepEqual(a, b) {
+			*ops = append(*ops, patchOp{Op: "replace", Path: path, Value: b})
+		}
+		return
+	}
+	keys := make([]string, 0, len(am)+len(bm))
+	for k := range am {
+		keys = append(keys, k)
+	}
+	for k := range bm {
+		if _, ok := am[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := path + "/" + pointerEscaper.Replace(k)
+		av, inA := am[k]
+		bv, inB := bm[k]
+		switch {
+		case !inB:
+			*ops = append(*ops, removeOp{Op: "remove", Path: p})
+		case !inA:
+			*ops = append(*ops, patchOp{Op: "add", Path: p, Value: bv})
+		default:
+			jsonDiff(p, av, bv, ops)
+		}
+	}
+}
+
+// pointerEscaper escapes a JSON Pointer (RFC 6901) reference token.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
@@ -0,0 +1,39 @@
+package model_reflect_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestJSONPatch(t *testing.T) {
+	a, _ := model_reflect.New(orderV1{})
+	b, _ := model_reflect.New(orderV2{})
+	data, err := a.JSONPatch(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ops []map[string]any
+	if err := json.Unmarshal(data, &ops); err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]any{
+		{"op": "add", "path": "/properties/Added", "value": map[string]any{"type": "integer"}},
+		{"op": "remove", "path": "/properties/Old"},
+		{"op": "replace", "path": "/properties/Status/type", "value": "string"},
+		{"op": "add", "path": "/properties/comment", "value": map[string]any{"type": "string"}},
+		{"op": "remove", "path": "/properties/note"},
+		{"op": "replace", "path": "/title", "value": "orderV2"},
+	}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("patch:\n%s", data)
+	}
+	if data, err := a.JSONPatch(a); err != nil || string(data) != "[]" {
+		t.Errorf("self patch: %s [%v]", data, err)
+	}
+	if _, err := a.JSONPatch(model_reflect.ModelInfo{}); err == nil {
+		t.Error("patch to empty model")
+	}
+}
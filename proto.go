@@ -0,0 +1,162 @@
+package model_reflect
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrUnsupported is returned when a type cannot be expressed in an output format.
+var ErrUnsupported = errors.New("unsupported type")
+
+var protoScalars = map[string]string{
+	"bool":    "bool",
+	"string":  "string",
+	"int":     "int64",
+	"int8":    "int32",
+	"int16":   "int32",
+	"int32":   "int32",
+	"int64":   "int64",
+	"uint":    "uint64",
+	"uint8":   "uint32",
+	"uint16":  "uint32",
+	"uint32":  "uint32",
+	"uint64":  "uint64",
+	"float32": "float",
+	"float64": "double",
+}
+
+// Proto returns a proto3 definition of the model and every named struct it
+// references. Field numbers follow the canonical (sorted) field order.
+func (m ModelInfo) Proto(pkg string) (string, error) {
+	if m.root == nil || m.root.Kind == KindNil {
+		return "", ErrNoType
+	}
+	if m.root.Kind != KindStruct || m.root.namedType() == "" {
+		return "", fmt.Errorf("%w %s", ErrUnnamed, m)
+	}
+	g := &protoGen{done: map[any]bool{}}
+	b := &strings.Builder{}
+	b.WriteString("syntax = \"proto3\";\n")
+	if pkg != "" {
+		b.WriteString("\npackage " + pkg + ";\n")
+	}
+	g.queue = append(g.queue, m.root)
+	for len(g.queue) > 0 {
+		n := g.queue[0]
+		g.queue = g.queue[1:]
+		if g.done[n.typeKey()] {
+			continue
+		}
+		g.done[n.typeKey()] = true
+		b.WriteString("\n")
+		if err := g.message(b, messageName(n.namedType()), n, ""); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+type protoGen struct {
+	done  map[any]bool
+	queue []*Model
+}
+
+func (g *protoGen) message(b *strings.Builder, name string, n *Model, indent string) error {
+	body := &strings.Builder{}
+	nested := &strings.Builder{}
+	for i, f := range n.Fields {
+		typ, err := g.fieldType(nested, f, indent+"  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(body, "%s  %s %s = %d;\n", indent, typ, snakeCase(f.Name), i+1)
+	}
+	fmt.Fprintf(b, "%smessage %s {\n%s%s%s}\n", indent, name, nested.String(), body.String(), indent)
+	return nil
+}
+
+func (g *protoGen) fieldType(nested *strings.Builder, f *Model, indent string) (string, error) {
+	switch {
+	case f.Kind == KindSlice && !isBytes(f), f.Kind == KindArray:
+		typ, err := g.elemType(nested, f.Elem, f.Name, indent)
+		return "repeated " + typ, err
+	case f.Kind == KindMap:
+		key, ok := protoScalars[f.Key.Repr]
+		if f.Key.Kind != KindScalar || !ok || key == "float" || key == "double" {
+			return "", fmt.Errorf("%w: map key %s of %s", ErrUnsupported, f.Key, f.Name)
+		}
+		elem, err := g.elemType(nested, f.Elem, f.Name, indent)
+		return "map<" + key + ", " + elem + ">", err
+	case (f.Kind == KindScalar || f.Kind == KindNullable) && f.Nullable:
+		typ, err := g.elemType(nested, f, f.Name, indent)
+		return "optional " + typ, err
+	}
+	return g.elemType(nested, f, f.Name, indent)
+}
+
+// elemType returns the proto type of a non-repeated value.
+func (g *protoGen) elemType(nested *strings.Builder, n *Model, name, indent string) (string, error) {
+	switch n.Kind {
+	case KindScalar, KindLiteral:
+		if typ, ok := protoScalars[n.Repr]; ok {
+			return typ, nil
+		}
+	case KindSlice:
+		if isBytes(n) {
+			return "bytes", nil
+		}
+	case KindNullable:
+		return g.elemType(nested, n.Elem, name, indent)
+	case KindOpaque:
+		if strings.Contains(n.Repr, "TextMarshaler") {
+			return "string", nil
+		}
+		return "bytes", nil
+	case KindLoop, KindRef:
+		if name := n.namedType(); name != "" {
+			return messageName(name), nil
+		}
+	case KindStruct:
+		if name := n.namedType(); name != "" {
+			g.queue = append(g.queue, n)
+			return messageName(name), nil
+		}
+		msg := messageName(name)
+		return msg, g.message(nested, msg, n, indent)
+	}
+	return "", fmt.Errorf("%w: %s of %s", ErrUnsupported, n, name)
+}
+
+func isBytes(n *Model) bool {
+	return n.Kind == KindSlice && n.Elem.Kind == KindScalar && n.Elem.Repr == "uint8"
+}
+
+// messageName returns name with its first letter upper cased.
+func messageName(name string) string {
+	if name == "" {
+		return name
+	}
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// snakeCase converts a CamelCase name to snake_case, keeping acronyms
+// together ("HTTPServer" becomes "http_server").
+func snakeCase(name string) string {
+	r := []rune(name)
+	b := strings.Builder{}
+	for i, c := range r {
+		if unicode.IsUpper(c) {
+			if i > 0 && (unicode.IsLower(r[i-1]) || unicode.IsDigit(r[i-1]) ||
+				(i+1 < len(r) && unicode.IsLower(r[i+1]) && unicode.IsUpper(r[i-1]))) {
+				b.WriteByte('_')
+			}
+			c = unicode.ToLower(c)
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package model_reflect_test
+
+import (
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type protoItem struct {
+	SKU   string
+	Price float64
+}
+
+type protoOrder struct {
+	OrderID  int64
+	Items    []protoItem
+	Labels   map[string]int32
+	Payload  []byte
+	Discount *float32
+	Meta     struct {
+		Source string
+	}
+}
+
+func TestProto(t *testing.T) {
+	model, _ := model_reflect.New(protoOrder{})
+	s, err := model.Proto("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `syntax = "proto3";
+
+package shop;
+
+message ProtoOrder {
+  message Meta {
+    string source = 1;
+  }
+  optional float discount = 1;
+  repeated ProtoItem items = 2;
+  map<string, int32> labels = 3;
+  Meta meta = 4;
+  int64 order_id = 5;
+  bytes payload = 6;
+}
+
+message ProtoItem {
+  double price = 1;
+  string sku = 2;
+}
+`
+	if s != want {
+		t.Errorf("proto:\n%s\nwant:\n%s", s, want)
+	}
+}
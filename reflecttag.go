@@ -0,0 +1,49 @@
+package model_reflect
+
+import (
+	"reflect"
+	"strings"
+)
+
+// reflectTag is the parsed reflect struct tag of a field. Besides "-",
+// which leaves the field out, the tag is a comma-separated list of
+// directives:
+//
+//	name=ID      names the field ID, overriding the name tags
+//	type=string  renders the field as string instead of its type
+//	opaque       renders the field by its type name without descending
+//
+// Any other tag is a literal that replaces the field's type, as in
+// reflect:"uuid".
+type reflectTag struct {
+	ignore  bool
+	name    string
+	typ     string
+	opaque  bool
+	literal string
+}
+
+func parseReflectTag(f reflect.StructField) reflectTag {
+	tag := f.Tag.Get("reflect")
+	if tag == "-" {
+		return reflectTag{ignore: true}
+	}
+	r := reflectTag{}
+	if tag == "" {
+		return r
+	}
+	for _, directive := range strings.Split(tag, ",") {
+		key, value, ok := strings.Cut(directive, "=")
+		switch {
+		case !ok && key == "opaque":
+			r.opaque = true
+		case ok && key == "name" && value != "":
+			r.name = value
+		case ok && key == "type" && value != "":
+			r.typ = value
+		default:
+			return reflectTag{literal: tag}
+		}
+	}
+	return r
+}
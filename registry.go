@@ -0,0 +1,182 @@
+package model_reflect
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	// ErrDuplicateName is returned when a model name is registered twice.
+	ErrDuplicateName = errors.New("duplicate model name")
+	// ErrDuplicateVersion is returned when a model version is registered twice.
+	ErrDuplicateVersion = errors.New("duplicate model version")
+	// ErrHashCollision is returned when a model hash is already registered
+	// under another name.
+	ErrHashCollision = errors.New("hash collision")
+	// ErrNotFound is returned when a model or version is not registered.
+	ErrNotFound = errors.New("model not found")
+)
+
+type (
+	// Registry is a set of named, versioned models addressable by name and
+	// by hash. It is safe for concurrent use.
+	Registry struct {
+		mu     sync.RWMutex
+		opts   []Option
+		byName map[string][]Entry
+		byHash map[uint64]Entry
+	}
+
+	// Entry is a registered model version. Version is empty for models
+	// registered without one.
+	Entry struct {
+		Name    string
+		Version string
+		Time    time.Time
+		Model   ModelInfo
+	}
+)
+
+// NewRegistry returns an empty registry reflecting models with opts.
+func NewRegistry(opts ...Option) *Registry {
+	return &Registry{opts: opts}
+}
+
+// Register reflects v and registers it under name. The name must not have
+// been registered before.
+func (r *Registry) Register(name string, v any) (ModelInfo, error) {
+	m, err := New(v, r.opts...)
+	if err != nil {
+		return m, err
+	}
+	return m, r.Add(name, m)
+}
+
+// MustRegister is like Register but panics on error.
+func (r *Registry) MustRegister(name string, v any) ModelInfo {
+	m, err := r.Register(name, v)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Add registers an already reflected model under name. The name must not
+// have been registered before.
+func (r *Registry) Add(name string, m ModelInfo) error {
+	return r.add(name, "", m, true)
+}
+
+// RegisterVersion reflects v and appends it to the history of name.
+func (r *Registry) RegisterVersion(name, version string, v any) (ModelInfo, error) {
+	m, err := New(v, r.opts...)
+	if err != nil {
+		return m, err
+	}
+	return m, r.AddVersion(name, version, m)
+}
+
+// AddVersion appends an already reflected model to the history of name.
+// Versions are kept in registration order.
+func (r *Registry) AddVersion(name, version string, m ModelInfo) error {
+	return r.add(name, version, m, false)
+}
+
+func (r *Registry) add(name, version string, m ModelInfo, unique bool) error {
+	hash := m.Hash()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.byName == nil {
+		r.byName = map[string][]Entry{}
+		r.byHash = map[uint64]Entry{}
+	}
+	history := r.byName[name]
+	if unique && len(history) > 0 {
+		return fmt.Errorf("%w %s", ErrDuplicateName, name)
+	}
+	for _, e := range history {
+		if e.Version == version {
+			return fmt.Errorf("%w %s %s", ErrDuplicateVersion, name, version)
+		}
+	}
+	if other, ok := r.byHash[hash]; ok && other.Name != name {
+		if other.Model.string == m.string {
+			return fmt.Errorf("%w: %s has the same model as %s", ErrHashCollision, name, other.Name)
+		}
+		return fmt.Errorf("%w: %s and %s hash to %x", ErrHashCollision, name, other.Name, hash)
+	}
+	e := Entry{Name: name, Version: version, Time: time.Now(), Model: m}
+	r.byName[name] = append(history, e)
+	r.byHash[hash] = e
+	return nil
+}
+
+// Lookup returns the latest model registered under name.
+func (r *Registry) Lookup(name string) (ModelInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	history := r.byName[name]
+	if len(history) == 0 {
+		return ModelInfo{}, false
+	}
+	return history[len(history)-1].Model, true
+}
+
+// LookupVersion returns the given version of name.
+func (r *Registry) LookupVersion(name, version string) (Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, e := range r.byName[name] {
+		if e.Version == version {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// LookupHash returns the latest model version registered with the given hash.
+func (r *Registry) LookupHash(hash uint64) (Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.byHash[hash]
+	return e, ok
+}
+
+// Versions returns the history of name in registration order.
+func (r *Registry) Versions(name string) []Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.byName[name])
+}
+
+// Changes returns the difference between two versions of name.
+func (r *Registry) Changes(name, from, to string) (ModelDiff, error) {
+	a, ok := r.LookupVersion(name, from)
+	if !ok {
+		return ModelDiff{}, fmt.Errorf("%w: %s %s", ErrNotFound, name, from)
+	}
+	b, ok := r.LookupVersion(name, to)
+	if !ok {
+		return ModelDiff{}, fmt.Errorf("%w: %s %s", ErrNotFound, name, to)
+	}
+	return a.Model.Diff(b.Model), nil
+}
+
+// List returns the latest version of all registered models sorted by name.
+func (r *Registry) List() []Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]Entry, 0, len(r.byName))
+	for _, history := range r.byName {
+		result = append(result, history[len(history)-1])
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,82 @@
+package model_reflect_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type regUser struct {
+	Name string
+}
+
+type regAccount struct {
+	Name string
+}
+
+type regOrder struct {
+	ID int
+}
+
+func TestRegistry(t *testing.T) {
+	reg := model_reflect.NewRegistry()
+	user := reg.MustRegister("User", regUser{})
+	if _, err := reg.Register("Order", regOrder{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reg.Register("User", regOrder{}); !errors.Is(err, model_reflect.ErrDuplicateName) {
+		t.Errorf("duplicate name: %v", err)
+	}
+	if _, err := reg.Register("Account", regAccount{}); !errors.Is(err, model_reflect.ErrHashCollision) {
+		t.Errorf("identical model: %v", err)
+	}
+	if e, ok := reg.LookupHash(user.Hash()); !ok || e.Name != "User" {
+		t.Errorf("lookup hash: %+v", e)
+	}
+	if _, ok := reg.Lookup("Account"); ok {
+		t.Error("failed registration was stored")
+	}
+	list := reg.List()
+	if len(list) != 2 || list[0].Name != "Order" || list[1].Name != "User" {
+		t.Errorf("list: %+v", list)
+	}
+}
+
+type orderV3 struct {
+	ID    int
+	Total float32
+}
+
+type orderV5 struct {
+	ID     int
+	Total  float64
+	Status string
+}
+
+func TestRegistryVersions(t *testing.T) {
+	reg := model_reflect.NewRegistry()
+	if _, err := reg.RegisterVersion("Order", "v3", orderV3{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reg.RegisterVersion("Order", "v5", orderV5{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reg.RegisterVersion("Order", "v5", orderV3{}); !errors.Is(err, model_reflect.ErrDuplicateVersion) {
+		t.Errorf("duplicate version: %v", err)
+	}
+	if v := reg.Versions("Order"); len(v) != 2 || v[0].Version != "v3" || v[1].Time.Before(v[0].Time) {
+		t.Errorf("versions: %+v", v)
+	}
+	d, err := reg.Changes("Order", "v3", "v5")
+	if err != nil || len(d.Changes) != 2 ||
+		d.Changes[0].Path != "Status" || d.Changes[1].Path != "Total" {
+		t.Errorf("changes: %+v [%v]", d.Changes, err)
+	}
+	if _, err := reg.Changes("Order", "v3", "v4"); !errors.Is(err, model_reflect.ErrNotFound) {
+		t.Errorf("unknown version: %v", err)
+	}
+	if m, _ := reg.Lookup("Order"); m.String() != "{ ID:int, Status:string, Total:float64 }" {
+		t.Errorf("latest: %s", m)
+	}
+}
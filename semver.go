@@ -0,0 +1,83 @@
+package model_reflect
+
+import "strings"
+
+// Bump is a semantic version increment.
+type Bump uint8
+
+const (
+	// BumpPatch is recommended when the wire format did not change.
+	BumpPatch Bump = iota
+	// BumpMinor is recommended when only optional fields were added or
+	// fields were made optional.
+	BumpMinor
+	// BumpMajor is recommended when fields were removed, renamed or retyped,
+	// required fields were added or made required or the tags recorded with
+	// WithTags changed.
+	BumpMajor
+)
+
+// String returns the name of the bump.
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "unknown"
+	}
+}
+
+// RecommendBump returns the version increment for a change from oldModel
+// to newModel. Added fields are optional when they are pointers or, with
+// WithOptionality, tagged omitempty or omitzero.
+func RecommendBump(oldModel, newModel ModelInfo) Bump {
+	bump := BumpPatch
+	for _, c := range oldModel.Diff(newModel).Changes {
+		if c.Kind == OptionalityChanged && becameOptional(c) {
+			bump = BumpMinor
+			continue
+		}
+		if c.Kind != FieldAdded {
+			return BumpMajor
+		}
+		if f := modelAt(newModel.root, c.Path); f == nil || !(f.Optional || f.Nullable) {
+			return BumpMajor
+		}
+		bump = BumpMinor
+	}
+	return bump
+}
+
+// modelAt returns the node at a change path such as "Lines[].Qty".
+func modelAt(n *Model, path string) *Model {
+	if path == "" {
+		return n
+	}
+	for _, name := range strings.Split(path, ".") {
+		elems := 0
+		for strings.HasSuffix(name, "[]") {
+			name = strings.TrimSuffix(name, "[]")
+			elems++
+		}
+		if name != "" {
+			if n == nil || n.Kind != KindStruct {
+				return nil
+			}
+			var field *Model
+			for _, f := range n.Fields {
+				if f.Name == name {
+					field = f
+				}
+			}
+			n = field
+		}
+		for ; elems > 0 && n != nil; elems-- {
+			n = n.Elem
+		}
+	}
+	return n
+}
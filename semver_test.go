@@ -0,0 +1,67 @@
+package model_reflect_test
+
+import (
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type bumpV1 struct {
+	ID    int
+	Lines []struct{ SKU string }
+}
+
+type bumpOptional struct {
+	ID    int
+	Note  *string
+	Lines []struct {
+		SKU  string
+		Memo *string
+	}
+}
+
+type bumpRequired struct {
+	ID    int
+	Lines []struct {
+		SKU string
+		Qty int
+	}
+}
+
+type bumpRetyped struct {
+	ID    string
+	Lines []struct{ SKU string }
+}
+
+func TestRecommendBump(t *testing.T) {
+	v1, _ := model_reflect.New(bumpV1{})
+	for _, c := range []struct {
+		v    any
+		want model_reflect.Bump
+	}{
+		{bumpV1{}, model_reflect.BumpPatch},
+		{bumpOptional{}, model_reflect.BumpMinor},
+		{bumpRequired{}, model_reflect.BumpMajor},
+		{bumpRetyped{}, model_reflect.BumpMajor},
+	} {
+		m, _ := model_reflect.New(c.v)
+		if got := model_reflect.RecommendBump(v1, m); got != c.want {
+			t.Errorf("bump %T: %s, want %s", c.v, got, c.want)
+		}
+	}
+	optional, _ := model_reflect.New(bumpOptional{})
+	if got := model_reflect.RecommendBump(optional, v1); got != model_reflect.BumpMajor {
+		t.Errorf("bump removed: %s", got)
+	}
+}
+
+func TestRecommendBumpOptionality(t *testing.T) {
+	required, _ := model_reflect.New(contactRequired{}, model_reflect.WithOptionality())
+	optional, _ := model_reflect.New(contactOptional{}, model_reflect.WithOptionality())
+	if got := model_reflect.RecommendBump(required, optional); got != model_reflect.BumpMinor {
+		t.Errorf("made optional: %s", got)
+	}
+	if got := model_reflect.RecommendBump(optional, required); got != model_reflect.BumpMajor {
+		t.Errorf("made required: %s", got)
+	}
+}
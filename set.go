@@ -0,0 +1,96 @@
+package model_reflect
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+type (
+	// ModelSet is a collection of models keyed by name, such as all
+	// messages a service can emit. Its hash does not depend on the order
+	// models were added in. The zero value is an empty set; a ModelSet is
+	// not safe for concurrent modification.
+	ModelSet struct {
+		models map[string]ModelInfo
+	}
+
+	// SetDiff is the difference between two model sets.
+	SetDiff struct {
+		Added   []string
+		Removed []string
+		// Changed holds the diff of every model present in both sets whose
+		// canonical form differs.
+		Changed map[string]ModelDiff
+	}
+)
+
+// Add adds or replaces the model registered under name.
+func (s *ModelSet) Add(name string, m ModelInfo) {
+	if s.models == nil {
+		s.models = map[string]ModelInfo{}
+	}
+	s.models[name] = m
+}
+
+// Remove removes the model registered under name.
+func (s *ModelSet) Remove(name string) {
+	delete(s.models, name)
+}
+
+// Get returns the model registered under name.
+func (s *ModelSet) Get(name string) (ModelInfo, bool) {
+	m, ok := s.models[name]
+	return m, ok
+}
+
+// Len returns the number of models in the set.
+func (s *ModelSet) Len() int {
+	return len(s.models)
+}
+
+// Names returns the names in the set in sorted order.
+func (s *ModelSet) Names() []string {
+	names := make([]string, 0, len(s.models))
+	for name := range s.models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Hash returns the hash of the names and model hashes of the set in name
+// order, using the default hasher.
+func (s *ModelSet) Hash() uint64 {
+	data := []byte{}
+	for _, name := range s.Names() {
+		data = appendBytes(data, []byte(name))
+		data = binary.LittleEndian.AppendUint64(data, s.models[name].Hash())
+	}
+	return sum64(DefaultHasher, data)
+}
+
+// Diff returns the difference from s to other.
+func (s *ModelSet) Diff(other *ModelSet) SetDiff {
+	d := SetDiff{Changed: map[string]ModelDiff{}}
+	for _, name := range s.Names() {
+		m := s.models[name]
+		o, ok := other.Get(name)
+		switch {
+		case !ok:
+			d.Removed = append(d.Removed, name)
+		case o.string != m.string:
+			d.Changed[name] = m.Diff(o)
+		}
+	}
+	for _, name := range other.Names() {
+		if _, ok := s.models[name]; !ok {
+			d.Added = append(d.Added, name)
+		}
+	}
+	return d
+}
+
+// Empty reports whether the sets are equal.
+func (d SetDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
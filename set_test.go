@@ -0,0 +1,39 @@
+package model_reflect_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestModelSet(t *testing.T) {
+	p, _ := model_reflect.New(point{})
+	s, _ := model_reflect.New(segment{})
+	o1, _ := model_reflect.New(orderV1{})
+	o2, _ := model_reflect.New(orderV2{})
+
+	a, b := &model_reflect.ModelSet{}, &model_reflect.ModelSet{}
+	a.Add("Point", p)
+	a.Add("Segment", s)
+	b.Add("Segment", s)
+	b.Add("Point", p)
+	if a.Hash() != b.Hash() || !a.Diff(b).Empty() || a.Len() != 2 {
+		t.Errorf("order dependent: %x %x", a.Hash(), b.Hash())
+	}
+
+	a.Add("Order", o1)
+	b.Add("Order", o2)
+	b.Remove("Point")
+	d := a.Diff(b)
+	if len(d.Added) != 0 || !reflect.DeepEqual(d.Removed, []string{"Point"}) || len(d.Changed) != 1 ||
+		d.Changed["Order"].Empty() || a.Hash() == b.Hash() {
+		t.Errorf("diff: %+v", d)
+	}
+	if got := b.Names(); !reflect.DeepEqual(got, []string{"Order", "Segment"}) {
+		t.Errorf("names: %v", got)
+	}
+	if (&model_reflect.ModelSet{}).Hash() != (&model_reflect.ModelSet{}).Hash() {
+		t.Error("empty set hash")
+	}
+}
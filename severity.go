@@ -0,0 +1,85 @@
+package model_reflect
+
+import (
+	"errors"
+)
+
+// Severity classifies a finding reported by New.
+type Severity uint8
+
+const (
+	// SeverityError findings are returned by New and listed by Errors.
+	SeverityError Severity = iota
+	// SeverityWarning findings are only listed by Warnings.
+	SeverityWarning
+	// SeverityIgnore findings are dropped.
+	SeverityIgnore
+)
+
+// ErrSkippedField is reported as a warning for fields left out of the model
+// because their type cannot be represented, such as interfaces without
+// implementations.
+var ErrSkippedField = errors.New("skipped field")
+
+// severityRule sets the severity of findings matching target.
+type severityRule struct {
+	target   error
+	severity Severity
+}
+
+// WithSeverity sets the severity of findings matching target with
+// errors.Is. By default ErrSkippedField is a warning and every other
+// finding an error. When several targets match a finding, the last
+// WithSeverity wins.
+func WithSeverity(target error, s Severity) Option {
+	return func(c *config) {
+		severities := make([]severityRule, len(c.severities), len(c.severities)+1)
+		copy(severities, c.severities)
+		c.severities = append(severities, severityRule{target, s})
+	}
+}
+
+// WithStrict reports every value that cannot be represented, such as func,
+// chan and unsafe.Pointer fields or interfaces without implementations, as
+// an ErrUnsupported or ErrSkippedField error carrying its path, so that
+// unserializable fields are caught in tests.
+func WithStrict() Option {
+	severity := WithSeverity(ErrSkippedField, SeverityError)
+	return func(c *config) {
+		c.strict = true
+		severity(c)
+	}
+}
+
+// severity returns the severity of err.
+func (c *config) severity(err error) Severity {
+	for i := len(c.severities) - 1; i >= 0; i-- {
+		if errors.Is(err, c.severities[i].target) {
+			return c.severities[i].severity
+		}
+	}
+	return SeverityError
+}
+
+// classify splits findings into errors and warnings.
+func (c *config) classify(findings []error) (errs, warnings []error) {
+	for _, err := range findings {
+		switch c.severity(err) {
+		case SeverityError:
+			errs = append(errs, err)
+		case SeverityWarning:
+			warnings = append(warnings, err)
+		}
+	}
+	return errs, warnings
+}
+
+// Errors returns the findings of SeverityError, the same as Errs.
+func (m ModelInfo) Errors() []error {
+	return m.Errs
+}
+
+// Warnings returns the findings of SeverityWarning.
+func (m ModelInfo) Warnings() []error {
+	return m.warnings
+}
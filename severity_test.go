@@ -0,0 +1,93 @@
+package model_reflect_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type emptyMeta struct {
+	Name string
+	Meta struct{}
+}
+
+func TestWarnings(t *testing.T) {
+	model, err := model_reflect.New(drawing{})
+	var pathErr *model_reflect.PathError
+	if err != nil || len(model.Errors()) != 0 || len(model.Warnings()) != 1 ||
+		!errors.Is(model.Warnings()[0], model_reflect.ErrSkippedField) ||
+		!errors.As(model.Warnings()[0], &pathErr) || pathErr.Path != "Main" {
+		t.Errorf("skipped field: %v %v", err, model.Warnings())
+	}
+	model, err = model_reflect.New(drawing{},
+		model_reflect.WithSeverity(model_reflect.ErrSkippedField, model_reflect.SeverityError))
+	if !errors.Is(err, model_reflect.ErrSkippedField) || len(model.Errors()) != 1 || len(model.Warnings()) != 0 {
+		t.Errorf("as error: %v %v", err, model.Warnings())
+	}
+}
+
+func TestWithSeverity(t *testing.T) {
+	model, err := model_reflect.New(emptyMeta{})
+	if !errors.Is(err, model_reflect.ErrEmptyStruct) || len(model.Warnings()) != 0 {
+		t.Errorf("default: %v %v", err, model.Warnings())
+	}
+	model, err = model_reflect.New(emptyMeta{},
+		model_reflect.WithSeverity(model_reflect.ErrEmptyStruct, model_reflect.SeverityWarning))
+	if err != nil || len(model.Warnings()) != 1 || !errors.Is(model.Warnings()[0], model_reflect.ErrEmptyStruct) {
+		t.Errorf("warning: %v %v", err, model.Warnings())
+	}
+	model, err = model_reflect.New(emptyMeta{},
+		model_reflect.WithSeverity(model_reflect.ErrEmptyStruct, model_reflect.SeverityIgnore))
+	if err != nil || len(model.Warnings()) != 0 || len(model.Errs) != 0 {
+		t.Errorf("ignored: %v %v", err, model.Warnings())
+	}
+}
+
+func TestWithSeverityOverlapping(t *testing.T) {
+	var loop model_reflect.LoopError
+	if _, err := model_reflect.New((*testA)(nil)); !errors.As(err, &loop) {
+		t.Fatalf("loop: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		model, err := model_reflect.New((*testA)(nil),
+			model_reflect.WithSeverity(loop, model_reflect.SeverityWarning),
+			model_reflect.WithSeverity(model_reflect.ErrLoopDetected, model_reflect.SeverityIgnore))
+		if err != nil || len(model.Warnings()) != 0 {
+			t.Fatalf("generic last: %v %v", err, model.Warnings())
+		}
+		model, err = model_reflect.New((*testA)(nil),
+			model_reflect.WithSeverity(model_reflect.ErrLoopDetected, model_reflect.SeverityIgnore),
+			model_reflect.WithSeverity(loop, model_reflect.SeverityWarning))
+		if err != nil || len(model.Warnings()) == 0 || !errors.Is(model.Warnings()[0], loop) {
+			t.Fatalf("specific last: %v %v", err, model.Warnings())
+		}
+	}
+}
+
+type unsupportedStruct struct {
+	Name     string
+	Callback func()
+	Events   chan int
+	Handlers []func()
+}
+
+func TestWithStrict(t *testing.T) {
+	if _, err := model_reflect.New(unsupportedStruct{}); err != nil {
+		t.Errorf("default: %v", err)
+	}
+	model, err := model_reflect.New(unsupportedStruct{}, model_reflect.WithStrict())
+	paths := map[string]bool{}
+	for _, e := range model.Errors() {
+		var pathErr *model_reflect.PathError
+		if errors.As(e, &pathErr) {
+			paths[pathErr.Path] = true
+		}
+	}
+	if err == nil || len(paths) != 3 || !paths["Callback"] || !paths["Events"] || !paths["Handlers[]"] {
+		t.Errorf("strict: %v", err)
+	}
+	if !errors.Is(err, model_reflect.ErrSkippedField) || !errors.Is(err, model_reflect.ErrUnsupported) {
+		t.Errorf("strict sentinels: %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+package model_reflect
+
+import (
+	"crypto/ed25519"
+)
+
+// Sign returns a detached ed25519 signature over the canonical string of
+// the model. It panics if priv is not a valid private key.
+func (m ModelInfo) Sign(priv ed25519.PrivateKey) []byte {
+	return ed25519.Sign(priv, []byte(m.string))
+}
+
+// Verify reports whether sig is a valid signature of the model by pub.
+func (m ModelInfo) Verify(pub ed25519.PublicKey, sig []byte) bool {
+	if len(pub) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(pub, []byte(m.string), sig)
+}
@@ -0,0 +1,31 @@
+package model_reflect_test
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestSign(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, _ := model_reflect.New(point{})
+	sig := model.Sign(priv)
+	if !model.Verify(pub, sig) {
+		t.Error("valid signature rejected")
+	}
+	other, _ := model_reflect.New(segment{})
+	if other.Verify(pub, sig) {
+		t.Error("signature of another model accepted")
+	}
+	stored := model_reflect.ModelInfo{}
+	if err := stored.UnmarshalText([]byte(model.String())); err != nil || !stored.Verify(pub, sig) {
+		t.Errorf("stored model: %v", err)
+	}
+	if model.Verify(pub[:10], sig) {
+		t.Error("short key accepted")
+	}
+}
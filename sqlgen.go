@@ -0,0 +1,109 @@
+package model_reflect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect selects the SQL flavour emitted by CreateTable.
+type Dialect uint8
+
+const (
+	// Postgres emits PostgreSQL column types.
+	Postgres Dialect = iota
+	// MySQL emits MySQL column types.
+	MySQL
+	// SQLite emits SQLite type affinities.
+	SQLite
+)
+
+// sqlTypes maps scalar kinds to column types, indexed by Dialect.
+var sqlTypes = map[string][3]string{
+	"bool":    {"boolean", "boolean", "INTEGER"},
+	"string":  {"text", "text", "TEXT"},
+	"int":     {"bigint", "bigint", "INTEGER"},
+	"int8":    {"smallint", "tinyint", "INTEGER"},
+	"int16":   {"smallint", "smallint", "INTEGER"},
+	"int32":   {"integer", "int", "INTEGER"},
+	"int64":   {"bigint", "bigint", "INTEGER"},
+	"uint":    {"numeric(20)", "bigint unsigned", "INTEGER"},
+	"uint8":   {"smallint", "tinyint unsigned", "INTEGER"},
+	"uint16":  {"integer", "smallint unsigned", "INTEGER"},
+	"uint32":  {"bigint", "int unsigned", "INTEGER"},
+	"uint64":  {"numeric(20)", "bigint unsigned", "INTEGER"},
+	"float32": {"real", "float", "REAL"},
+	"float64": {"double precision", "double", "REAL"},
+	"bytes":   {"bytea", "blob", "BLOB"},
+	"json":    {"jsonb", "json", "TEXT"},
+}
+
+// CreateTable returns a CREATE TABLE statement for the model. Column names
+// come from the db tag (db:"-" skips the field) and default to the snake
+// cased field name. Pointer fields are nullable, and nested structs, slices
+// and maps are stored as JSON columns.
+func (m ModelInfo) CreateTable(table string, dialect Dialect) (string, error) {
+	if m.root == nil || m.root.Kind == KindNil {
+		return "", ErrNoType
+	}
+	if m.root.Kind != KindStruct {
+		return "", fmt.Errorf("%w: %s is not a struct", ErrUnsupported, m)
+	}
+	if dialect > SQLite {
+		return "", fmt.Errorf("%w: dialect %d", ErrUnsupported, dialect)
+	}
+	columns := []string{}
+	for _, f := range m.root.Fields {
+		name := strings.Split(f.Tag.Get("db"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = snakeCase(f.Name)
+		}
+		typ, err := sqlType(f, dialect)
+		if err != nil {
+			return "", err
+		}
+		column := "  " + quoteIdent(name, dialect) + " " + typ
+		if !f.Nullable {
+			column += " NOT NULL"
+		}
+		columns = append(columns, column)
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n);\n",
+		quoteIdent(table, dialect), strings.Join(columns, ",\n")), nil
+}
+
+func sqlType(n *Model, dialect Dialect) (string, error) {
+	key := ""
+	switch n.Kind {
+	case KindNullable:
+		return sqlType(n.Elem, dialect)
+	case KindScalar, KindLiteral:
+		key = n.Repr
+	case KindOpaque:
+		key = "string"
+		if !strings.Contains(n.Repr, "TextMarshaler") {
+			key = "bytes"
+		}
+	case KindSlice:
+		key = "json"
+		if isBytes(n) {
+			key = "bytes"
+		}
+	case KindArray, KindMap, KindStruct, KindLoop, KindRef:
+		key = "json"
+	}
+	typ, ok := sqlTypes[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %s of %s", ErrUnsupported, n, n.Name)
+	}
+	return typ[dialect], nil
+}
+
+func quoteIdent(name string, dialect Dialect) string {
+	if dialect == MySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
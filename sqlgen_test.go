@@ -0,0 +1,63 @@
+package model_reflect_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type sqlUser struct {
+	ID       int64 `db:"user_id"`
+	Email    string
+	Nickname *string
+	Avatar   []byte
+	Settings map[string]string
+	Password string `db:"-"`
+}
+
+func TestCreateTable(t *testing.T) {
+	model, _ := model_reflect.New(sqlUser{})
+	tests := map[model_reflect.Dialect]string{
+		model_reflect.Postgres: `CREATE TABLE "users" (
+  "avatar" bytea NOT NULL,
+  "email" text NOT NULL,
+  "user_id" bigint NOT NULL,
+  "nickname" text,
+  "settings" jsonb NOT NULL
+);
+`,
+		model_reflect.MySQL: "CREATE TABLE `users` (\n" +
+			"  `avatar` blob NOT NULL,\n" +
+			"  `email` text NOT NULL,\n" +
+			"  `user_id` bigint NOT NULL,\n" +
+			"  `nickname` text,\n" +
+			"  `settings` json NOT NULL\n" +
+			");\n",
+	}
+	for dialect, want := range tests {
+		s, err := model.CreateTable("users", dialect)
+		if err != nil || s != want {
+			t.Errorf("dialect %d: %s [%v]\nwant:\n%s", dialect, s, err, want)
+		}
+	}
+}
+
+type sqlAccount struct {
+	ID    int64
+	Name  sql.NullString
+	Score sql.NullFloat64
+}
+
+func TestCreateTableSQLNulls(t *testing.T) {
+	model, _ := model_reflect.New(sqlAccount{}, model_reflect.WithSQLNulls())
+	want := `CREATE TABLE "accounts" (
+  "id" bigint NOT NULL,
+  "name" text,
+  "score" double precision
+);
+`
+	if s, err := model.CreateTable("accounts", model_reflect.Postgres); err != nil || s != want {
+		t.Errorf("%s [%v]", s, err)
+	}
+}
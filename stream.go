@@ -0,0 +1,66 @@
+package model_reflect
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"reflect"
+)
+
+// ErrNotStreamable is returned by StreamHash for hashers that need the whole
+// canonical form at once, such as the argon2id default.
+var ErrNotStreamable = errors.New("hasher cannot stream")
+
+// StreamHasher is a Hasher that can also digest a model incrementally. The
+// first size bytes of the stream's Sum must equal Sum(model, size) for sizes
+// up to the stream's Size.
+type StreamHasher interface {
+	Hasher
+	Stream() hash.Hash
+}
+
+// StreamHash returns the Hash that New(v, opts...) would report, writing the
+// canonical form into the hasher as it is rendered instead of building the
+// string first. The model tree is still built, so this saves the string and
+// its copies but does not bound peak memory by itself. The hasher set with
+// WithHasher must be a StreamHasher, such as SHA256 or HMACHasher. Results
+// are not cached.
+func StreamHash(v any, opts ...Option) (uint64, error) {
+	c := newConfig(opts...)
+	c.setValue(reflect.ValueOf(v))
+	return c.streamHash(reflect.TypeOf(v))
+}
+
+// StreamHash is a shorthand for StreamHash(v, WithConfig(c)).
+func (c Config) StreamHash(v any) (uint64, error) {
+	return StreamHash(v, WithConfig(c))
+}
+
+func (c *config) streamHash(t reflect.Type) (uint64, error) {
+	h, ok := c.hasher.(StreamHasher)
+	if !ok {
+		return 0, fmt.Errorf("%w: %T", ErrNotStreamable, c.hasher)
+	}
+	root, findings := c.build(t)
+	errs, _ := c.classify(findings)
+	w := h.Stream()
+	io.WriteString(w, formatPrefix(c.format))
+	if _, err := root.WriteTo(w); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(w.Sum(nil)), errors.Join(errs...)
+}
+
+// Stream returns a SHA-256 hash.
+func (sha256Hasher) Stream() hash.Hash {
+	return sha256.New()
+}
+
+// Stream returns an HMAC keyed with Key.
+func (h HMACHasher) Stream() hash.Hash {
+	return hmac.New(hashOrSHA256(h.Hash), h.Key)
+}
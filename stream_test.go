@@ -0,0 +1,40 @@
+package model_reflect_test
+
+import (
+	"crypto/sha512"
+	"errors"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestStreamHash(t *testing.T) {
+	for _, opts := range [][]model_reflect.Option{
+		{model_reflect.WithHasher(model_reflect.SHA256)},
+		{model_reflect.WithHasher(model_reflect.SHA256), model_reflect.WithFormat(model_reflect.FormatV2)},
+		{model_reflect.WithHasher(model_reflect.HMACHasher{Key: []byte("secret")})},
+		{model_reflect.WithHasher(model_reflect.HMACHasher{Key: []byte("secret"), Hash: sha512.New})},
+	} {
+		want, _ := model_reflect.New(walkOrder{}, opts...)
+		got, err := model_reflect.StreamHash(walkOrder{}, opts...)
+		if err != nil || got != want.Hash() {
+			t.Errorf("stream: %x, want %x [%v]", got, want.Hash(), err)
+		}
+	}
+	cfg := model_reflect.NewConfig(model_reflect.WithHasher(model_reflect.SHA256))
+	want, wantErr := cfg.New(pathErrorStruct{})
+	if got, err := cfg.StreamHash(pathErrorStruct{}); got != want.Hash() || err == nil || err.Error() != wantErr.Error() {
+		t.Errorf("stream errors: %x [%v]", got, err)
+	}
+	event := &valueEvent{Payload: valuePoint{}, Tags: map[string]any{"a": "x"}}
+	opts := []model_reflect.Option{
+		model_reflect.WithHasher(model_reflect.SHA256), model_reflect.WithValues(), model_reflect.WithCycleRefs(),
+	}
+	want, _ = model_reflect.New(event, opts...)
+	if got, err := model_reflect.StreamHash(event, opts...); err != nil || got != want.Hash() {
+		t.Errorf("values: %x, want %x [%v]", got, want.Hash(), err)
+	}
+	if _, err := model_reflect.StreamHash(point{}); !errors.Is(err, model_reflect.ErrNotStreamable) {
+		t.Errorf("default hasher: %v", err)
+	}
+}
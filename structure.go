@@ -0,0 +1,73 @@
+package model_reflect
+
+import (
+	"sort"
+	"strings"
+)
+
+// Structure returns the canonical form without field names and tags, with
+// the fields of every struct ordered by their types, as in
+// { int, string }. Renaming fields does not change it.
+func (m ModelInfo) Structure() string {
+	if m.root == nil {
+		return ""
+	}
+	var b strings.Builder
+	p := printer{w: &b, structural: true}
+	p.write(m.root)
+	return b.String()
+}
+
+// StructuralHash returns the hash of Structure. Comparing it along with
+// Hash tells pure renames, which keep it, from structural changes.
+func (m ModelInfo) StructuralHash() uint64 {
+	return sum64(m.hasher(), []byte(m.Structure()))
+}
+
+// FieldNames returns the field names of the model as encoders write them,
+// without their types, nesting the names of struct fields, including those
+// of slice, array and map elements, as in { id, items{ qty, sku } }.
+// Changing the width of a number does not change it, but changing the case
+// of a tag name does.
+func (m ModelInfo) FieldNames() string {
+	var b strings.Builder
+	writeNames(&b, elemStruct(m.root))
+	return b.String()
+}
+
+// NameHash returns the hash of FieldNames, for schemaless stores where key
+// renames break readers but type changes may not.
+func (m ModelInfo) NameHash() uint64 {
+	return sum64(m.hasher(), []byte(m.FieldNames()))
+}
+
+func writeNames(b *strings.Builder, n *Model) {
+	if n == nil {
+		return
+	}
+	b.WriteString("{ ")
+	for i, f := range n.Fields {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(f.wire())
+		writeNames(b, elemStruct(f))
+	}
+	b.WriteString(" }")
+}
+
+// writeStructure writes the fields of n without names, sorted.
+func (p *printer) writeStructure(n *Model) {
+	fields := make([]string, len(n.Fields))
+	for i, f := range n.Fields {
+		var b strings.Builder
+		fp := printer{w: &b, structural: true}
+		if f.Optional {
+			fp.WriteString("?")
+		}
+		fp.write(f)
+		fields[i] = b.String()
+	}
+	sort.Strings(fields)
+	p.WriteString("{ " + strings.Join(fields, ", ") + " }")
+}
@@ -0,0 +1,82 @@
+package model_reflect_test
+
+import (
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type structureV1 struct {
+	Alpha int
+	Beta  string
+	Items []struct{ SKU string }
+}
+
+type structureRenamed struct {
+	Zeta  int
+	Gamma string
+	Lines []struct{ Code string }
+}
+
+type structureRetyped struct {
+	Alpha int64
+	Beta  string
+	Items []struct{ SKU string }
+}
+
+func TestStructuralHash(t *testing.T) {
+	v1, _ := model_reflect.New(structureV1{})
+	renamed, _ := model_reflect.New(structureRenamed{})
+	retyped, _ := model_reflect.New(structureRetyped{})
+	if got := v1.Structure(); got != "{ []{ string }, int, string }" {
+		t.Errorf("structure: %s", got)
+	}
+	if v1.StructuralHash() != renamed.StructuralHash() || v1.Hash() == renamed.Hash() {
+		t.Error("structure: rename changed the structural hash")
+	}
+	if v1.StructuralHash() == retyped.StructuralHash() {
+		t.Error("structure: retype kept the structural hash")
+	}
+	optional, _ := model_reflect.New(structureV1{}, model_reflect.WithOptionality())
+	if optional.Structure() != v1.Structure() {
+		t.Errorf("structure: %s", optional.Structure())
+	}
+	if empty := (model_reflect.ModelInfo{}); empty.Structure() != "" {
+		t.Error("structure: empty model")
+	}
+}
+
+func TestNameHash(t *testing.T) {
+	v1, _ := model_reflect.New(structureV1{})
+	renamed, _ := model_reflect.New(structureRenamed{})
+	retyped, _ := model_reflect.New(structureRetyped{})
+	if got := v1.FieldNames(); got != "{ Alpha, Beta, Items{ SKU } }" {
+		t.Errorf("names: %s", got)
+	}
+	if v1.NameHash() != retyped.NameHash() || v1.Hash() == retyped.Hash() {
+		t.Error("names: retype changed the name hash")
+	}
+	if v1.NameHash() == renamed.NameHash() {
+		t.Error("names: rename kept the name hash")
+	}
+	type (
+		lower struct {
+			ID int `json:"id"`
+		}
+		upper struct {
+			ID int `json:"ID"`
+		}
+		title struct {
+			ID int `json:"Id"`
+		}
+	)
+	a, _ := model_reflect.New(lower{})
+	b, _ := model_reflect.New(upper{})
+	c, _ := model_reflect.New(title{})
+	if a.FieldNames() != "{ id }" || a.NameHash() == b.NameHash() || a.NameHash() == c.NameHash() {
+		t.Errorf("names: case-only rename %s %s %s", a.FieldNames(), b.FieldNames(), c.FieldNames())
+	}
+	if scalar, _ := model_reflect.New(0); scalar.FieldNames() != "" {
+		t.Errorf("names: scalar %s", scalar.FieldNames())
+	}
+}
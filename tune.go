@@ -0,0 +1,48 @@
+package model_reflect
+
+import (
+	"runtime"
+	"time"
+)
+
+// tuneMaxMemory caps the argon2 memory chosen by TuneHasher, in KiB.
+const tuneMaxMemory = 64 * 1024
+
+// TuneHasher benchmarks argon2id on the host and returns the strongest
+// parameters whose Sum over a typical model stays within targetLatency.
+// Memory is doubled up to 64 MiB before more passes are added. When even
+// the smallest parameters exceed the budget they are returned anyway.
+//
+// The result depends on the host and its load: tune once and persist the
+// parameters, since changing them changes every hash.
+func TuneHasher(targetLatency time.Duration) HashInfo {
+	threads := runtime.NumCPU()
+	if threads > 4 {
+		threads = 4
+	}
+	best := HashInfo{Time: 1, Memory: 8 * uint32(threads), Threads: uint8(threads)}
+	sample := make([]byte, 1024)
+	for next := best; ; {
+		next = next.tuneStep()
+		start := time.Now()
+		next.Sum(sample, 8)
+		if time.Since(start) > targetLatency {
+			return best
+		}
+		best = next
+	}
+}
+
+// tuneStep returns the next stronger parameters after h: memory doubled up
+// to tuneMaxMemory, then one more pass.
+func (h HashInfo) tuneStep() HashInfo {
+	switch {
+	case h.Memory >= tuneMaxMemory:
+		h.Time++
+	case h.Memory > tuneMaxMemory/2:
+		h.Memory = tuneMaxMemory
+	default:
+		h.Memory *= 2
+	}
+	return h
+}
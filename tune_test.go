@@ -0,0 +1,27 @@
+package model_reflect_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-modern/model_reflect"
+)
+
+func TestTuneHasher(t *testing.T) {
+	h := model_reflect.TuneHasher(5 * time.Millisecond)
+	if err := h.Validate(); err != nil || h.Memory > 64*1024 {
+		t.Errorf("tune: %+v [%v]", h, err)
+	}
+	if h := model_reflect.TuneHasher(0); h.Time != 1 || h.Memory != 8*uint32(h.Threads) || h.Validate() != nil {
+		t.Errorf("tune: zero budget %+v", h)
+	}
+	h = model_reflect.HashInfo{Time: 1, Memory: 24, Threads: 3}
+	for h.Time == 1 {
+		if h = model_reflect.TuneStep(h); h.Memory > 64*1024 {
+			t.Fatalf("tune: step overshot %+v", h)
+		}
+	}
+	if h.Memory != 64*1024 || h.Time != 2 {
+		t.Errorf("tune: three threads %+v", h)
+	}
+}
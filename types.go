@@ -0,0 +1,45 @@
+package model_reflect
+
+import (
+	"reflect"
+	"sync"
+)
+
+var registeredTypes = struct {
+	sync.RWMutex
+	m map[reflect.Type]string
+}{}
+
+// RegisterType overrides the canonical representation of t with repr for
+// every model, which is useful for third-party types that cannot carry a
+// reflect tag.
+func RegisterType(t reflect.Type, repr string) {
+	registeredTypes.Lock()
+	defer registeredTypes.Unlock()
+	registeredTypes.m = addTypeName(registeredTypes.m, t, repr)
+	ClearCache()
+}
+
+// WithType is like RegisterType for a single call.
+func WithType(t reflect.Type, repr string) Option {
+	return func(c *config) {
+		c.types = addTypeName(c.types, t, repr)
+	}
+}
+
+func registeredTypeNames() map[reflect.Type]string {
+	registeredTypes.RLock()
+	defer registeredTypes.RUnlock()
+	return registeredTypes.m
+}
+
+// addTypeName returns a copy of m with t mapped to name. Type name tables
+// are never modified in place, so they can be shared between configs.
+func addTypeName(m map[reflect.Type]string, t reflect.Type, name string) map[reflect.Type]string {
+	result := make(map[reflect.Type]string, len(m)+1)
+	for k, v := range m {
+		result[k] = v
+	}
+	result[t] = name
+	return result
+}
@@ -0,0 +1,37 @@
+package model_reflect_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type decimal struct {
+	//nolint:unused
+	value []byte
+	//nolint:unused
+	exp int32
+}
+
+type objectID [12]byte
+
+type invoice struct {
+	Amount  decimal
+	Owner   objectID
+	Payload func()
+}
+
+func TestRegisterType(t *testing.T) {
+	model, _ := model_reflect.New(invoice{},
+		model_reflect.WithType(reflect.TypeOf(decimal{}), "decimal"),
+		model_reflect.WithType(reflect.TypeOf(func() {}), "callback"))
+	if model.String() != "{ Amount:decimal, Owner:[12]uint8, Payload:callback }" {
+		t.Errorf("WithType: %s", model)
+	}
+	model_reflect.RegisterType(reflect.TypeOf(objectID{}), "objectid")
+	model, _ = model_reflect.New(invoice{})
+	if model.String() != "{ Amount:{  }, Owner:objectid }" {
+		t.Errorf("RegisterType: %s", model)
+	}
+}
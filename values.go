@@ -0,0 +1,122 @@
+package model_reflect
+
+import (
+	"reflect"
+	"sort"
+)
+
+// WithValues makes New, NewFromValue and NewContext model interface fields
+// of the value passed to them by the dynamic types they hold, so that
+// concrete runtime models can be fingerprinted and not just static types.
+// Every interface is kept as a union of the types found in it, added to
+// the implementations registered for it, and is rendered as () when it
+// only holds nil. Models reflected from values are not cached.
+func WithValues() Option {
+	return func(c *config) {
+		c.values = true
+	}
+}
+
+// setValue records v as the value to resolve interfaces from if values
+// are modeled.
+func (c *config) setValue(v reflect.Value) {
+	if c.values {
+		c.value = v
+	}
+}
+
+// resolver adds the dynamic types held by the interfaces of a value to the
+// union nodes of its model.
+type resolver struct {
+	c      *config
+	errs   *[]error
+	unions map[*Model]bool
+	// active holds the pointers being resolved, to stop at cycles.
+	active map[pointer]bool
+}
+
+// pointer identifies a pointer value by address and type.
+type pointer struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// resolveValues resolves the interfaces of c.value in root.
+func (c *config) resolveValues(root *Model, errs *[]error) {
+	r := &resolver{c: c, errs: errs, unions: map[*Model]bool{}, active: map[pointer]bool{}}
+	r.resolve(root, c.value, nil, "")
+}
+
+// resolve resolves the interfaces of v in n, which was built with types and
+// path.
+func (r *resolver) resolve(n *Model, v reflect.Value, types []reflect.Type, path string) {
+	if !v.IsValid() || n.Kind == KindLoop || !r.hasUnion(n) {
+		return
+	}
+	for v.Kind() == reflect.Pointer {
+		p := pointer{v.Pointer(), v.Type()}
+		if v.IsNil() || r.active[p] {
+			return
+		}
+		r.active[p] = true
+		defer delete(r.active, p)
+		v = v.Elem()
+	}
+	// Interfaces are left out of types: values cannot nest without end
+	// the way interface types can.
+	if n.Type != nil && n.Kind != KindUnion {
+		types = append(types, n.Type)
+	}
+	switch {
+	case n.Kind == KindUnion && v.Kind() == reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+		variant := r.c.typeToNode(v.Type(), types, path, r.errs)
+		r.resolve(variant, v, types, path)
+		s := variant.String()
+		for _, other := range n.Variants {
+			if other.String() == s {
+				return
+			}
+		}
+		n.Variants = append(n.Variants, variant)
+		sort.SliceStable(n.Variants, func(i, j int) bool {
+			return n.Variants[i].String() < n.Variants[j].String()
+		})
+	case n.Kind == KindStruct && v.Kind() == reflect.Struct:
+		for _, f := range n.Fields {
+			if f.Index == nil {
+				continue
+			}
+			if fv, err := v.FieldByIndexErr(f.Index); err == nil {
+				r.resolve(f, fv, types, joinPath(path, f.Name))
+			}
+		}
+	case (n.Kind == KindSlice || n.Kind == KindArray) && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array):
+		for i := 0; i < v.Len(); i++ {
+			r.resolve(n.Elem, v.Index(i), types, path+"[]")
+		}
+	case n.Kind == KindMap && v.Kind() == reflect.Map:
+		for it := v.MapRange(); it.Next(); {
+			r.resolve(n.Key, it.Key(), types, path+"[key]")
+			r.resolve(n.Elem, it.Value(), types, path+"[]")
+		}
+	}
+}
+
+// hasUnion reports whether n or any of its descendants is a union, which
+// values can only change the model of when it is.
+func (r *resolver) hasUnion(n *Model) bool {
+	found, ok := r.unions[n]
+	if ok {
+		return found
+	}
+	found = n.Kind == KindUnion
+	n.eachChild(func(c *Model) {
+		found = r.hasUnion(c) || found
+	})
+	r.unions[n] = found
+	return found
+}
@@ -0,0 +1,47 @@
+package model_reflect_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type (
+	valueEvent struct {
+		Kind    string
+		Payload any
+		Tags    map[string]any
+		Next    *valueEvent
+	}
+	valuePoint struct{ X, Y int }
+)
+
+func TestWithValues(t *testing.T) {
+	event := &valueEvent{
+		Payload: valuePoint{},
+		Tags:    map[string]any{"a": "x", "b": []any{true}, "c": "y"},
+		Next:    &valueEvent{Payload: []any{true}},
+	}
+	if model, _ := model_reflect.New(event, model_reflect.WithCycleRefs()); model.String() != "{ Kind:string, Next:@valueEvent, Tags:map[string]<?> }" {
+		t.Errorf("static: %s", model)
+	}
+	want := "{ Kind:string, Next:@valueEvent, Payload:({ X:int, Y:int }), Tags:map[string]([](bool)|string) }"
+	model, err := model_reflect.New(event, model_reflect.WithValues(), model_reflect.WithCycleRefs())
+	if err != nil || model.String() != want {
+		t.Errorf("values: %s [%v]", model, err)
+	}
+	model, err = model_reflect.NewFromValue(reflect.ValueOf(event), model_reflect.WithValues(), model_reflect.WithCycleRefs())
+	if err != nil || model.String() != want {
+		t.Errorf("from value: %s [%v]", model, err)
+	}
+	cycle := new(any)
+	*cycle = cycle
+	if model, err := model_reflect.New(cycle, model_reflect.WithValues()); err != nil || model.String() != "(())" {
+		t.Errorf("cycle: %s [%v]", model, err)
+	}
+	model, _ = model_reflect.New((*valueEvent)(nil), model_reflect.WithValues(), model_reflect.WithCycleRefs())
+	if model.String() != "{ Kind:string, Next:@valueEvent, Payload:(), Tags:map[string]() }" {
+		t.Errorf("nil: %s", model)
+	}
+}
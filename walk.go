@@ -0,0 +1,134 @@
+package model_reflect
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+type (
+	// FieldInfo describes a resolved field of a model.
+	FieldInfo struct {
+		// Name is the resolved field name and GoName the name of the Go field.
+		Name   string
+		GoName string
+		// Type is the canonical representation of the field's type.
+		Type     string
+		Tag      reflect.StructTag
+		Embedded bool
+		// Depth is the number of embedded structs the field was promoted
+		// through and Index its index sequence for reflect.Value.FieldByIndex
+		// in the enclosing struct.
+		Depth int
+		Index []int
+		Model *Model
+	}
+
+	// WalkFunc is called by Walk for every field. Path holds the names of the
+	// enclosing fields followed by the field's own name.
+	WalkFunc func(path []string, f FieldInfo) error
+)
+
+// SkipField can be returned by a WalkFunc to skip the fields nested in the
+// current field.
+var SkipField = errors.New("skip field") //nolint:revive,stylecheck
+
+// Fields returns the resolved fields of a struct model in canonical order,
+// with the fields of embedded structs flattened as in the canonical form.
+// It returns nil for models of other kinds.
+func (m ModelInfo) Fields() []FieldInfo {
+	if m.root == nil || m.root.Kind != KindStruct {
+		return nil
+	}
+	result := make([]FieldInfo, len(m.root.Fields))
+	for i, f := range m.root.Fields {
+		result[i] = f.fieldInfo()
+	}
+	return result
+}
+
+// Field returns the field at a dotted path of resolved names such as
+// "Items.Meta.ID". Like Walk it descends through the elements of slices,
+// arrays and maps.
+func (m ModelInfo) Field(path string) (FieldInfo, bool) {
+	n := m.root
+	for _, name := range strings.Split(path, ".") {
+		n = elemStruct(n)
+		if n == nil {
+			return FieldInfo{}, false
+		}
+		i := slices.IndexFunc(n.Fields, func(f *Model) bool { return f.Name == name })
+		if i < 0 {
+			return FieldInfo{}, false
+		}
+		n = n.Fields[i]
+	}
+	return n.fieldInfo(), true
+}
+
+// elemStruct returns the struct n holds, if any, looking through the
+// elements of slices, arrays and maps.
+func elemStruct(n *Model) *Model {
+	for n != nil && (n.Kind == KindSlice || n.Kind == KindArray || n.Kind == KindMap) {
+		n = n.Elem
+	}
+	if n == nil || n.Kind != KindStruct {
+		return nil
+	}
+	return n
+}
+
+// Walk calls fn for every field of the model in canonical order, descending
+// into nested structs including the elements of slices, arrays and maps.
+// Fields of embedded structs are reported at the level they are promoted to.
+// Walk stops at the first error returned by fn other than SkipField.
+func (m ModelInfo) Walk(fn WalkFunc) error {
+	return walkModel(m.root, nil, fn)
+}
+
+func walkModel(n *Model, path []string, fn WalkFunc) error {
+	for n != nil && (n.Kind == KindSlice || n.Kind == KindArray || n.Kind == KindMap) {
+		if n.Kind == KindMap {
+			if err := walkModel(n.Key, path, fn); err != nil {
+				return err
+			}
+		}
+		n = n.Elem
+	}
+	if n == nil || n.Kind != KindStruct {
+		return nil
+	}
+	for _, f := range n.Fields {
+		p := append(slices.Clip(path), f.Name)
+		err := fn(p, f.fieldInfo())
+		if err == SkipField {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := walkModel(f, p, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *Model) fieldInfo() FieldInfo {
+	depth := 0
+	if len(n.Index) > 1 {
+		depth = len(n.Index) - 1
+	}
+	return FieldInfo{
+		Name:     n.Name,
+		GoName:   n.GoName,
+		Type:     n.String(),
+		Tag:      n.Tag,
+		Embedded: n.Embedded,
+		Depth:    depth,
+		Index:    slices.Clone(n.Index),
+		Model:    n,
+	}
+}
@@ -0,0 +1,100 @@
+package model_reflect_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type walkLine struct {
+	SKU string `json:"sku"`
+	Qty int
+}
+
+type walkOrder struct {
+	ID    int
+	Lines []walkLine
+	Meta  map[string]struct{ Note string }
+}
+
+func TestWalk(t *testing.T) {
+	model, _ := model_reflect.New(walkOrder{})
+	got := []string{}
+	err := model.Walk(func(path []string, f model_reflect.FieldInfo) error {
+		got = append(got, strings.Join(path, ".")+" "+f.Type)
+		return nil
+	})
+	want := []string{
+		"ID int",
+		"Lines []{ Qty:int, Sku:string }",
+		"Lines.Qty int",
+		"Lines.Sku string",
+		"Meta map[string]{ Note:string }",
+		"Meta.Note string",
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("walk: %q [%v]", got, err)
+	}
+
+	got = got[:0]
+	_ = model.Walk(func(path []string, f model_reflect.FieldInfo) error {
+		got = append(got, strings.Join(path, "."))
+		if f.Name == "Lines" {
+			return model_reflect.SkipField
+		}
+		return nil
+	})
+	if !reflect.DeepEqual(got, []string{"ID", "Lines", "Meta", "Meta.Note"}) {
+		t.Errorf("skip: %q", got)
+	}
+}
+
+type walkBase struct {
+	ID      int
+	Created string
+}
+
+type walkUser struct {
+	walkBase
+	Name string `json:"name"`
+}
+
+func TestFields(t *testing.T) {
+	model, _ := model_reflect.New(walkUser{})
+	got := []string{}
+	for _, f := range model.Fields() {
+		got = append(got, fmt.Sprintf("%s/%s %s %d %v", f.Name, f.GoName, f.Type, f.Depth, f.Index))
+	}
+	want := []string{"Created/Created string 1 [0 1]", "ID/ID int 1 [0 0]", "Name/Name string 0 [1]"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fields: %q", got)
+	}
+	if f := model.Fields()[2]; f.Tag.Get("json") != "name" {
+		t.Errorf("tag: %q", f.Tag)
+	}
+	if scalar, _ := model_reflect.New(0); scalar.Fields() != nil {
+		t.Error("scalar fields")
+	}
+}
+
+func TestField(t *testing.T) {
+	model, _ := model_reflect.New(walkOrder{})
+	for path, want := range map[string]string{
+		"ID":        "int",
+		"Lines":     "[]{ Qty:int, Sku:string }",
+		"Lines.Sku": "string",
+		"Meta.Note": "string",
+	} {
+		if f, ok := model.Field(path); !ok || f.Type != want {
+			t.Errorf("field %s: %q %t", path, f.Type, ok)
+		}
+	}
+	for _, path := range []string{"", "Missing", "ID.X", "Lines.SKU", "Meta.Note.X"} {
+		if _, ok := model.Field(path); ok {
+			t.Errorf("field %s found", path)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package model_reflect
+
+import (
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+var wellKnown = struct {
+	sync.RWMutex
+	m map[reflect.Type]string
+}{
+	m: map[reflect.Type]string{
+		reflect.TypeOf(time.Time{}):       "time",
+		durationType:                      "duration",
+		reflect.TypeOf(url.URL{}):         "url",
+		reflect.TypeOf(big.Int{}):         "bigint",
+		reflect.TypeOf(big.Float{}):       "bigfloat",
+		reflect.TypeOf(big.Rat{}):         "bigrat",
+		reflect.TypeOf(net.IP{}):          "ipaddr",
+		reflect.TypeOf(netip.Addr{}):      "ipaddr",
+		reflect.TypeOf(netip.Prefix{}):    "ipprefix",
+		reflect.TypeOf(json.RawMessage{}): "raw-json",
+	},
+}
+
+// WithWellKnownTypes renders well-known types by a stable name instead of
+// their layout or interfaces: time.Time as time, time.Duration as duration,
+// url.URL as url, big.Int, big.Float and big.Rat as bigint, bigfloat and
+// bigrat, net.IP and netip.Addr as ipaddr, netip.Prefix as ipprefix,
+// json.RawMessage as raw-json and types named UUID with a [16]byte layout
+// as uuid. Well-known types keep their name whichever interfaces make types
+// opaque, so that the math/big types do not change with WithJSONMarshalers,
+// for example. The table can be extended with RegisterWellKnownType.
+func WithWellKnownTypes() Option {
+	return func(c *config) {
+		c.wellKnown = registeredWellKnownTypes()
+	}
+}
+
+// RegisterWellKnownType adds t to the well-known types rendered as name when
+// WithWellKnownTypes is used.
+func RegisterWellKnownType(t reflect.Type, name string) {
+	wellKnown.Lock()
+	defer wellKnown.Unlock()
+	wellKnown.m = addTypeName(wellKnown.m, t, name)
+	ClearCache()
+}
+
+// RegisterRawType adds t to the well-known types as raw-format, for types
+// holding data in an encoding that is passed through unchanged, like
+// json.RawMessage is rendered as raw-json.
+func RegisterRawType(t reflect.Type, format string) {
+	RegisterWellKnownType(t, "raw-"+format)
+}
+
+func registeredWellKnownTypes() map[reflect.Type]string {
+	wellKnown.RLock()
+	defer wellKnown.RUnlock()
+	return wellKnown.m
+}
+
+// wellKnownName returns the well-known name of t, if any.
+func (c *config) wellKnownName(t reflect.Type) (string, bool) {
+	if c.wellKnown == nil {
+		return "", false
+	}
+	if name, ok := c.wellKnown[t]; ok {
+		return name, true
+	}
+	if t.Name() == "UUID" && t.Kind() == reflect.Array && t.Len() == 16 && t.Elem().Kind() == reflect.Uint8 {
+		return "uuid", true
+	}
+	return "", false
+}
+
+// typeNamesKey returns a deterministic description of m for the cache.
+func typeNamesKey(m map[reflect.Type]string) string {
+	if m == nil {
+		return ""
+	}
+	keys := make([]string, 0, len(m))
+	for t, name := range m {
+		keys = append(keys, t.PkgPath()+"."+t.String()+"="+name)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
@@ -0,0 +1,90 @@
+package model_reflect_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-modern/model_reflect"
+)
+
+type UUID [16]byte
+
+type Money struct{ Units int64 }
+
+type wellKnownStruct struct {
+	At      time.Time
+	Timeout time.Duration
+	Link    *url.URL
+	ID      UUID
+	Price   Money
+}
+
+func TestWithWellKnownTypes(t *testing.T) {
+	model, _ := model_reflect.New(struct {
+		ID      UUID
+		Timeout time.Duration
+	}{})
+	if model.String() != "{ ID:[16]uint8, Timeout:int64 }" {
+		t.Errorf("default: %s", model)
+	}
+	model_reflect.RegisterWellKnownType(reflect.TypeOf(Money{}), "money")
+	model, _ = model_reflect.New(wellKnownStruct{}, model_reflect.WithWellKnownTypes())
+	if model.String() != "{ At:time, ID:uuid, Link:url, Price:money, Timeout:duration }" {
+		t.Errorf("well-known: %s", model)
+	}
+}
+
+func TestWellKnownBigTypes(t *testing.T) {
+	type amounts struct {
+		Count big.Int
+		Price *big.Float
+		Ratio big.Rat
+	}
+	want := "{ Count:bigint, Price:bigfloat, Ratio:bigrat }"
+	for _, opts := range [][]model_reflect.Option{
+		{model_reflect.WithWellKnownTypes()},
+		{model_reflect.WithWellKnownTypes(), model_reflect.WithJSONMarshalers()},
+	} {
+		if model, err := model_reflect.New(amounts{}, opts...); err != nil || model.String() != want {
+			t.Errorf("%s [%v]", model, err)
+		}
+	}
+}
+
+func TestWellKnownNetworkTypes(t *testing.T) {
+	type route struct {
+		Gateway net.IP
+		Source  netip.Addr
+		Target  netip.Prefix
+	}
+	model, _ := model_reflect.New(route{}, model_reflect.WithWellKnownTypes())
+	if model.String() != "{ Gateway:ipaddr, Source:ipaddr, Target:ipprefix }" {
+		t.Errorf("%s", model)
+	}
+}
+
+type rawYAML []byte
+
+func TestWellKnownRawTypes(t *testing.T) {
+	type document struct {
+		Body json.RawMessage
+		Meta rawYAML
+	}
+	model_reflect.RegisterRawType(reflect.TypeOf(rawYAML{}), "yaml")
+	t.Cleanup(func() {
+		model_reflect.UnregisterWellKnownType(reflect.TypeOf(rawYAML{}))
+	})
+	if model, _ := model_reflect.New(document{}); model.String() != "{ Body:[]uint8, Meta:[]uint8 }" {
+		t.Errorf("default: %s", model)
+	}
+	model, _ := model_reflect.New(document{}, model_reflect.WithWellKnownTypes())
+	if model.String() != "{ Body:raw-json, Meta:raw-yaml }" {
+		t.Errorf("raw: %s", model)
+	}
+}